@@ -0,0 +1,89 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import "fmt"
+
+// ChecksumPolicy specifies how sentence checksums are handled during
+// parsing.
+type ChecksumPolicy int
+
+const (
+	// ChecksumReport checks checksums that are present. A mismatch
+	// is reported by a *ChecksumError after the destination has been
+	// filled. Sentences without a checksum are accepted.
+	ChecksumReport ChecksumPolicy = iota
+
+	// ChecksumRequire rejects sentences without a checksum with
+	// ErrNoChecksum and sentences with a mismatched checksum with
+	// a *ChecksumError before the destination is filled.
+	ChecksumRequire
+
+	// ChecksumIgnore does not check sentence or
+	// tag block checksums.
+	ChecksumIgnore
+)
+
+// ParseOptions holds options controlling sentence parsing. The zero value
+// holds the options used by ParseTo and Parse.
+type ParseOptions struct {
+	// Checksum is the checksum policy.
+	Checksum ChecksumPolicy
+
+	// StrictHex specifies that checksums with
+	// lowercase hexadecimal digits are rejected
+	// with ErrBadChecksum.
+	StrictHex bool
+
+	// StrictSpace specifies that white space
+	// following the sentence, including line
+	// terminators, is not removed before
+	// parsing.
+	StrictSpace bool
+}
+
+// ChecksumError is the error returned when the checksum given in a sentence
+// or tag block does not match the checksum of its bytes. ChecksumErrors
+// match ErrChecksum when tested with errors.Is.
+//
+// Checksum mismatches were previously returned as ErrChecksum itself.
+// Code that compares errors with ErrChecksum using == must use errors.Is
+// instead.
+type ChecksumError struct {
+	Computed byte // Computed is the checksum of the bytes.
+	Expected byte // Expected is the checksum given in the text.
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("nmea: checksum mismatch: computed 0x%02X, expected 0x%02X", e.Computed, e.Expected)
+}
+
+// Is returns whether target is ErrChecksum.
+func (e *ChecksumError) Is(target error) bool { return target == ErrChecksum }
+
+// verify returns the error for f under the policy that must be returned
+// before the sentence's fields are parsed.
+func (f frame) verify(policy ChecksumPolicy) error {
+	if policy != ChecksumRequire {
+		return nil
+	}
+	if !f.hasSum {
+		return ErrNoChecksum
+	}
+	if f.sum != f.want {
+		return &ChecksumError{Computed: f.sum, Expected: f.want}
+	}
+	return nil
+}
+
+// report returns the error for f under the policy that must be returned
+// after the sentence's fields are parsed with the error err. A checksum
+// mismatch takes precedence over err.
+func (f frame) report(policy ChecksumPolicy, err error) error {
+	if policy == ChecksumReport && f.sum != f.want {
+		return &ChecksumError{Computed: f.sum, Expected: f.want}
+	}
+	return err
+}
@@ -0,0 +1,144 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var checksumPolicyTests = []struct {
+	opts     ParseOptions
+	sentence string
+	want     HDT
+	err      error
+}{
+	{
+		sentence: "$GPHDT,1,T*2A",
+		want:     HDT{Type: "GPHDT", Heading: 1, Checksum: 0x2a},
+	},
+	{
+		sentence: "$GPHDT,1,T*2a",
+		want:     HDT{Type: "GPHDT", Heading: 1, Checksum: 0x2a},
+	},
+	{
+		sentence: "$GPHDT,1,T*2A\r\n",
+		want:     HDT{Type: "GPHDT", Heading: 1, Checksum: 0x2a},
+	},
+	{
+		sentence: "$GPHDT,1,T",
+		want:     HDT{Type: "GPHDT", Heading: 1},
+	},
+	{
+		sentence: "$GPHDT,1,T*2B",
+		want:     HDT{Type: "GPHDT", Heading: 1, Checksum: 0x2b},
+		err:      &ChecksumError{Computed: 0x2a, Expected: 0x2b},
+	},
+	{
+		sentence: "$GPHDT,1,T*2",
+		err:      ErrBadChecksum,
+	},
+	{
+		sentence: "$GPHDT,1,T*2G",
+		err:      ErrBadChecksum,
+	},
+	{
+		opts:     ParseOptions{StrictHex: true},
+		sentence: "$GPHDT,1,T*2a",
+		err:      ErrBadChecksum,
+	},
+	{
+		opts:     ParseOptions{StrictSpace: true},
+		sentence: "$GPHDT,1,T*2A\r\n",
+		err:      ErrBadChecksum,
+	},
+	{
+		opts:     ParseOptions{Checksum: ChecksumRequire},
+		sentence: "$GPHDT,1,T*2A",
+		want:     HDT{Type: "GPHDT", Heading: 1, Checksum: 0x2a},
+	},
+	{
+		opts:     ParseOptions{Checksum: ChecksumRequire},
+		sentence: "$GPHDT,1,T",
+		err:      ErrNoChecksum,
+	},
+	{
+		opts:     ParseOptions{Checksum: ChecksumRequire},
+		sentence: "$GPHDT,1,T*2B",
+		err:      &ChecksumError{Computed: 0x2a, Expected: 0x2b},
+	},
+	{
+		opts:     ParseOptions{Checksum: ChecksumIgnore},
+		sentence: "$GPHDT,1,T",
+		want:     HDT{Type: "GPHDT", Heading: 1},
+	},
+	{
+		opts:     ParseOptions{Checksum: ChecksumIgnore},
+		sentence: "$GPHDT,1,T*2B",
+		want:     HDT{Type: "GPHDT", Heading: 1, Checksum: 0x2b},
+	},
+	{
+		sentence: `\s:r3669961,c:1697040000*78\$GPHDT,1,T*2A`,
+		err:      &ChecksumError{Computed: 0x77, Expected: 0x78},
+	},
+	{
+		opts:     ParseOptions{Checksum: ChecksumIgnore},
+		sentence: `\s:r3669961,c:1697040000*78\$GPHDT,1,T*2A`,
+		want:     HDT{Type: "GPHDT", Heading: 1, Checksum: 0x2a},
+	},
+}
+
+func TestChecksumPolicy(t *testing.T) {
+	for _, test := range checksumPolicyTests {
+		r := NewDefaultRegistry()
+		r.SetParseOptions(test.opts)
+
+		var got HDT
+		err := r.ParseTo(&got, test.sentence)
+		if !reflect.DeepEqual(err, test.err) {
+			t.Errorf("unexpected error for %q with %+v: got:%v want:%v", test.sentence, test.opts, err, test.err)
+		}
+		if got != test.want {
+			t.Errorf("unexpected result for %q with %+v:\ngot: %#v\nwant:%#v", test.sentence, test.opts, got, test.want)
+		}
+
+		v, err := r.ParseBytes([]byte(test.sentence))
+		if !reflect.DeepEqual(err, test.err) {
+			t.Errorf("unexpected error for %q with %+v from Parse: got:%v want:%v", test.sentence, test.opts, err, test.err)
+		}
+		if err != nil && test.want == (HDT{}) {
+			continue
+		}
+		if v != test.want {
+			t.Errorf("unexpected result for %q with %+v from Parse:\ngot: %#v\nwant:%#v", test.sentence, test.opts, v, test.want)
+		}
+	}
+}
+
+func TestHighChecksum(t *testing.T) {
+	const sentence = "$PXTXT,caf\xe9*F1"
+	got, err := ParseSentence(sentence)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Checksum != 0xf1 || !got.ChecksumValid {
+		t.Errorf("unexpected checksum: got:%#x valid:%t want:0xf1 valid:true", got.Checksum, got.ChecksumValid)
+	}
+	if got.String() != sentence {
+		t.Errorf("unexpected round trip: got:%q want:%q", got.String(), sentence)
+	}
+}
+
+func TestChecksumError(t *testing.T) {
+	err := error(&ChecksumError{Computed: 0x2a, Expected: 0x9b})
+	if !errors.Is(err, ErrChecksum) {
+		t.Errorf("ChecksumError does not match ErrChecksum")
+	}
+	const want = "nmea: checksum mismatch: computed 0x2A, expected 0x9B"
+	if err.Error() != want {
+		t.Errorf("unexpected error text: got:%q want:%q", err.Error(), want)
+	}
+}
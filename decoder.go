@@ -0,0 +1,203 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// ErrLineTooLong is returned by Decoder when an input line is longer
+// than the decoder's maximum line length.
+var ErrLineTooLong = errors.New("nmea: line too long")
+
+// DefaultMaxLineLength is the maximum line length used by a Decoder
+// when its MaxLineLength field is zero.
+const DefaultMaxLineLength = 1024
+
+// Decoder reads and parses NMEA 0183 sentences from an input stream.
+//
+// Lines are terminated by a newline with an optional preceding carriage
+// return. Blank lines and lines without a sentence sigil are skipped. If a
+// line holds more than one sigil, for example because a partial sentence
+// was interrupted by another, only the text from the last sigil is used.
+// A tag block immediately preceding the sentence is available from the
+// TagBlock method.
+//
+// Errors relating to a single sentence, such as ErrChecksum,
+// ErrNotRegistered or ErrLineTooLong, are returned from the call that
+// read the sentence and do not prevent later sentences from being read.
+// Errors from the underlying reader are sticky.
+type Decoder struct {
+	// MaxLineLength is the maximum length of an input line,
+	// not including the line terminator. If MaxLineLength is
+	// zero, DefaultMaxLineLength is used.
+	MaxLineLength int
+
+	// Registry is the registry used by Decode to select
+	// destination types and by Decode, DecodeTo and
+	// TagBlock for its parse options. If Registry is nil, the registry
+	// used by the package-level Register and Parse functions
+	// is used.
+	Registry *Registry
+
+	r *bufio.Reader
+
+	line     []byte
+	sentence string
+	tagBlock TagBlock
+
+	err error
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next sentence from its input and returns the result of
+// calling Parse on it. At the end of the input stream, Decode returns
+// nil, io.EOF.
+func (d *Decoder) Decode() (interface{}, error) {
+	err := d.next()
+	if err != nil {
+		return nil, err
+	}
+	if d.Registry == nil {
+		return Parse(d.sentence)
+	}
+	return d.Registry.Parse(d.sentence)
+}
+
+// DecodeTo reads the next sentence from its input and fills the fields
+// of dst as described for ParseTo, using the parse options of the
+// Decoder's Registry if it is not nil. At the end of the input stream,
+// DecodeTo returns io.EOF.
+func (d *Decoder) DecodeTo(dst interface{}) error {
+	err := d.next()
+	if err != nil {
+		return err
+	}
+	if d.Registry == nil {
+		return ParseTo(dst, d.sentence)
+	}
+	return d.Registry.ParseTo(dst, d.sentence)
+}
+
+// Sentence returns the raw text of the most recently read sentence,
+// starting from its sigil and excluding the line terminator.
+func (d *Decoder) Sentence() string {
+	return d.sentence
+}
+
+// TagBlock returns the tag block preceding the most recently read
+// sentence. If the sentence has no tag block, the zero TagBlock is
+// returned.
+func (d *Decoder) TagBlock() TagBlock {
+	return d.tagBlock
+}
+
+// next reads lines until it finds one holding a sentence sigil and
+// stores the sentence text in d.sentence and any tag block preceding
+// it in d.tagBlock.
+func (d *Decoder) next() error {
+	d.sentence = ""
+	d.tagBlock = TagBlock{}
+	for {
+		if d.err != nil {
+			return d.err
+		}
+		err := d.readLine()
+		if err == ErrLineTooLong {
+			return err
+		}
+		if err != nil {
+			d.err = err
+			if err != io.EOF || len(d.line) == 0 {
+				return err
+			}
+		}
+
+		line := bytes.TrimRight(d.line, " \t\r\n")
+		idx := bytes.LastIndexAny(line, "$!")
+		if idx < 0 {
+			// Skip blank and garbage lines.
+			continue
+		}
+		d.sentence = string(line[idx:])
+		start, err := tagBlocksStart(line[:idx])
+		if err != nil {
+			return err
+		}
+		if start < idx {
+			r := d.Registry
+			if r == nil {
+				r = defaultRegistry
+			}
+			d.tagBlock, _, err = r.ParseTagBlock(string(line[start:idx]))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// tagBlocksStart returns the start of the run of consecutive tag blocks
+// at the end of b, or len(b) if b does not end with a tag block.
+func tagBlocksStart(b []byte) (int, error) {
+	start := len(b)
+	for start > 0 && b[start-1] == '\\' {
+		open := bytes.LastIndexByte(b[:start-1], '\\')
+		if open < 0 {
+			if start == len(b) {
+				return 0, ErrTagBlock
+			}
+			break
+		}
+		start = open
+	}
+	return start, nil
+}
+
+// readLine reads a complete line into d.line. If the line is longer than
+// the maximum allowed line length, the remainder of the line is discarded
+// and ErrLineTooLong is returned.
+func (d *Decoder) readLine() error {
+	max := d.MaxLineLength
+	if max == 0 {
+		max = DefaultMaxLineLength
+	}
+	d.line = d.line[:0]
+	tooLong := false
+	for {
+		frag, err := d.r.ReadSlice('\n')
+		if !tooLong {
+			d.line = append(d.line, frag...)
+			// Allow for a CRLF line terminator.
+			if len(bytes.TrimRight(d.line, "\r\n")) > max {
+				tooLong = true
+				d.line = d.line[:0]
+			}
+		}
+		switch err {
+		case bufio.ErrBufferFull:
+			continue
+		case nil:
+			if tooLong {
+				return ErrLineTooLong
+			}
+			return nil
+		default:
+			if tooLong && err == io.EOF {
+				d.err = err
+				return ErrLineTooLong
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	input := "\r\n" +
+		"GGA,123519,4807.038,N,01131.000,W,1,2,3,4,M,5,M,,*41\r\n" + // Truncated start.
+		"$GPHDT,1,T*2A\r\n" +
+		"   \r\n" +
+		"$GPHDT,1,T*2B\r\n" + // Bad checksum.
+		"$GPXXX,1,2,3*00\r\n" + // Not registered.
+		"$GPRMC,22$GPHDT,2,T*29\n" + // Interleaved.
+		"$GPHDT," + strings.Repeat("1", 100) + ",T\r\n" + // Too long.
+		"$GPHDT,3,T*28"
+
+	want := []struct {
+		sentence string
+		val      interface{}
+		err      error
+	}{
+		{sentence: "$GPHDT,1,T*2A", val: HDT{Type: "GPHDT", Heading: 1, Checksum: 0x2a}},
+		{sentence: "$GPHDT,1,T*2B", val: HDT{Type: "GPHDT", Heading: 1, Checksum: 0x2b}, err: ErrChecksum},
+		{sentence: "$GPXXX,1,2,3*00", err: ErrNotRegistered},
+		{sentence: "$GPHDT,2,T*29", val: HDT{Type: "GPHDT", Heading: 2, Checksum: 0x29}},
+		{err: ErrLineTooLong},
+		{sentence: "$GPHDT,3,T*28", val: HDT{Type: "GPHDT", Heading: 3, Checksum: 0x28}},
+		{err: io.EOF},
+		{err: io.EOF},
+	}
+
+	d := NewDecoder(strings.NewReader(input))
+	d.MaxLineLength = 82
+	for i, w := range want {
+		got, err := d.Decode()
+		if !errors.Is(err, w.err) {
+			t.Errorf("unexpected error for sentence %d: got:%v want:%v", i, err, w.err)
+		}
+		if d.Sentence() != w.sentence {
+			t.Errorf("unexpected sentence %d: got:%q want:%q", i, d.Sentence(), w.sentence)
+		}
+		if !reflect.DeepEqual(got, w.val) {
+			t.Errorf("unexpected value for sentence %d:\ngot: %#v\nwant:%#v", i, got, w.val)
+		}
+	}
+}
+
+func TestDecoderDecodeTo(t *testing.T) {
+	d := NewDecoder(strings.NewReader("$GPHDT,1,T*2A\n$GPHDT,2,T*29\n"))
+	for _, want := range []float64{1, 2} {
+		var got HDT
+		err := d.DecodeTo(&got)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if got.Heading != want {
+			t.Errorf("unexpected heading: got:%v want:%v", got.Heading, want)
+		}
+	}
+	err := d.DecodeTo(&HDT{})
+	if err != io.EOF {
+		t.Errorf("unexpected error at end of stream: got:%v want:%v", err, io.EOF)
+	}
+}
@@ -9,8 +9,10 @@
 // specified in field tags with the name "nmea". A destination struct must have
 // an initial field with the name Type and a struct tag `nmea:"type"` where type
 // is the type of the NMEA sentence or a regexp matching the sentence type if it
-// is quoted in //. If the Type field is a string, the type will be stored in the
-// field.
+// is quoted in //. A type with the talker ID "--", for example "--GGA", matches
+// the formatter from any talker. If the Type field is a string, the type will be
+// stored in the field. SplitType returns the talker ID and formatter of a
+// stored type.
 //
 // Parsing methods that are available are:
 //
@@ -18,8 +20,100 @@
 //  - "string": set the field to the literal NMEA value
 //  - "latlon": set the field to a latitude or longitude parsed from the NMEA value
 //  - "date":   set the field to a data parsed from the NMEA value in the form ddmmyy.
-//  - "time":   set the field to a time parsed from the NMEA value in the form hhmmss.ss.
+//  - "time":   set a time.Time or TimeOfDay field to a time parsed from the NMEA value in the form hhmmss.ss,
+//              with any number of fractional second digits.
+//
+// The following methods use more than one NMEA value:
+//
+//  - "lat":      set the field to a signed latitude parsed from a value and N/S hemisphere pair
+//  - "lon":      set the field to a signed longitude parsed from a value and E/W hemisphere pair
+//  - "position": set a Position field from latitude and longitude value and hemisphere pairs
+//
+// A struct field, embedded or named, whose type has tagged fields is filled
+// from consecutive NMEA values according to the tags of its own fields, for
+// example the four values of an embedded LatLon. The field itself does not
+// need a tag.
+//
+// Repeated groups of NMEA values may be parsed into array and slice fields.
+// An array field uses the tag method for each of its elements and consumes
+// as many NMEA values as it has elements. A slice field must specify the
+// number of elements in the sentence with a repeat option, for example
+// `nmea:"string,repeat=14"`, and is set to hold the elements up to the last
+// element with a non-empty NMEA value. Elements that are structs have their
+// fields filled according to their own field tags, and the method may be
+// omitted, for example `nmea:"repeat=4"`.
+//
+// A final slice field may be given the "rest" option to hold all the remaining
+// NMEA values of the sentence, for example `nmea:"number,rest"`. If the method
+// is omitted for a slice of non-struct elements, the "string" method is used.
+//
+// Tagged and untagged fields fill consecutive NMEA values in the order they
+// are declared. A field may instead be placed at an explicit position with
+// an @n option, for example `nmea:"number,@3"`, where position 0 is the
+// sentence type; fields without a position continue from the preceding
+// field. When any field of a struct has an explicit position, untagged
+// fields do not consume NMEA values, so the struct may skip values, order
+// its fields freely and hold untagged helper fields. Positions within
+// nested structs are relative to the start of the struct, and fields may
+// not overlap.
+//
+// A field tagged with a const option, for example `nmea:"const=T"`, holds a
+// constant NMEA value such as a unit. The NMEA value must be empty or equal
+// to the constant, otherwise parsing fails with a *ConstError. A string field
+// is set to the NMEA value, and the constant is always written by Marshal.
+//
+// The Status, FAAMode and SelectionMode types may be filled with the "string"
+// method, and the FixQuality and FixType types with the "number" method. NMEA
+// values that are not one of the types' defined constants are rejected with
+// ErrEnum.
+//
+// Fields may parse their own NMEA values. The "text" method fills a field
+// that implements FieldUnmarshaler or encoding.TextUnmarshaler with the raw
+// NMEA value, and formats it with FieldMarshaler or encoding.TextMarshaler.
+// The "text" method is used when a tag omits the method for these types, and
+// untagged FieldUnmarshaler fields are filled as if they were tagged. A
+// destination type that implements Unmarshaler is given all the fields of
+// the sentence and its tags are not used. Similarly, a type that implements
+// Marshaler provides all the fields of the sentence it is written as.
+//
+// A field that is a pointer to a type that a method can fill is set to nil when
+// the NMEA value is empty, allowing missing values to be distinguished from
+// zero values. Non-pointer fields are set to their zero value when the NMEA
+// value is empty, except for "date" and "time" fields, for which an empty
+// value is an error.
 //
 // A special case method is "checksum" which will write the value of the sentence
-// checksum if it is available.
+// checksum if it is available. Additional methods may be added with
+// RegisterMethod.
+//
+// By default, checksums are checked when they are present and a mismatch is
+// reported by a *ChecksumError after the fields have been filled. The error
+// matches ErrChecksum with errors.Is but is not equal to it, so checks using
+// err == ErrChecksum must be changed to use errors.Is. A tag block checksum
+// mismatch prevents parsing unless checksums are ignored. Checksum digits
+// may be upper or lower case and white space following the sentence is
+// ignored. A Registry may be configured with ParseOptions to require or
+// ignore checksums and to reject lowercase digits and trailing white space.
+//
+// Sentences of any type may be parsed without interpretation into a Sentence
+// by ParseSentence, and a Registry may be set to return a Sentence for types
+// that are not registered.
+//
+// Query sentences, such as "$CCGPQ,GGA", are parsed into and written from
+// a Query, and a Responder holds the latest sentences of a talker so that
+// queries may be answered.
+//
+// Satellites in view reported over a sequence of GSV sentences may be
+// assembled into a SkyView for each talker by a GSVAssembler.
+//
+// NMEA 4.10 tag blocks preceding a sentence are checked and skipped by the
+// parsing functions. Their parameters are available from ParseTagBlock,
+// ParseWithTagBlock and Decoder's TagBlock method, and AppendTagBlock
+// writes a tag block.
+//
+// The Marshal and AppendSentence functions perform the reverse operation,
+// writing the fields of a tagged struct into a NMEA sentence using the same
+// methods and appending the sentence checksum. A "latlon" value that is
+// followed by an "E" or "W" hemisphere value is written as a longitude with
+// three degree digits.
 package nmea
@@ -0,0 +1,244 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Status is an NMEA data status indicator. The zero value
+// indicates that the status was not given.
+type Status string
+
+const (
+	StatusValid   Status = "A" // Data valid.
+	StatusInvalid Status = "V" // Data invalid or warning.
+)
+
+func (s Status) valid() bool {
+	switch s {
+	case "", StatusValid, StatusInvalid:
+		return true
+	}
+	return false
+}
+
+func (s Status) String() string {
+	switch s {
+	case "":
+		return "unknown"
+	case StatusValid:
+		return "valid"
+	case StatusInvalid:
+		return "invalid"
+	}
+	return "Status(" + strconv.Quote(string(s)) + ")"
+}
+
+// FAAMode is an NMEA 2.3 FAA mode indicator. The zero
+// value indicates that the mode was not given.
+type FAAMode string
+
+const (
+	FAAModeAutonomous   FAAMode = "A" // Autonomous mode.
+	FAAModeDifferential FAAMode = "D" // Differential mode.
+	FAAModeEstimated    FAAMode = "E" // Estimated (dead reckoning) mode.
+	FAAModeRTKFloat     FAAMode = "F" // RTK float mode.
+	FAAModeManual       FAAMode = "M" // Manual input mode.
+	FAAModeNotValid     FAAMode = "N" // Data not valid.
+	FAAModePrecise      FAAMode = "P" // Precise mode.
+	FAAModeRTKInteger   FAAMode = "R" // RTK integer mode.
+	FAAModeSimulator    FAAMode = "S" // Simulator mode.
+
+	// FAAModeInvalid is used in place of FAAModeNotValid
+	// by some sentences, for example THS.
+	FAAModeInvalid FAAMode = "V"
+)
+
+var faaModeNames = map[FAAMode]string{
+	"":                  "unknown",
+	FAAModeAutonomous:   "autonomous",
+	FAAModeDifferential: "differential",
+	FAAModeEstimated:    "estimated",
+	FAAModeRTKFloat:     "RTK float",
+	FAAModeManual:       "manual",
+	FAAModeNotValid:     "not valid",
+	FAAModePrecise:      "precise",
+	FAAModeRTKInteger:   "RTK integer",
+	FAAModeSimulator:    "simulator",
+	FAAModeInvalid:      "invalid",
+}
+
+func (m FAAMode) valid() bool {
+	_, ok := faaModeNames[m]
+	return ok
+}
+
+func (m FAAMode) String() string {
+	name, ok := faaModeNames[m]
+	if !ok {
+		return "FAAMode(" + strconv.Quote(string(m)) + ")"
+	}
+	return name
+}
+
+// SelectionMode is a GSA fix selection mode. The zero
+// value indicates that the mode was not given.
+type SelectionMode string
+
+const (
+	SelectionAutomatic SelectionMode = "A" // Automatic 2D/3D selection.
+	SelectionManual    SelectionMode = "M" // Manual 2D/3D selection.
+)
+
+func (m SelectionMode) valid() bool {
+	switch m {
+	case "", SelectionAutomatic, SelectionManual:
+		return true
+	}
+	return false
+}
+
+func (m SelectionMode) String() string {
+	switch m {
+	case "":
+		return "unknown"
+	case SelectionAutomatic:
+		return "automatic"
+	case SelectionManual:
+		return "manual"
+	}
+	return "SelectionMode(" + strconv.Quote(string(m)) + ")"
+}
+
+// FixQuality is a GGA fix quality indicator.
+type FixQuality int
+
+const (
+	FixInvalid    FixQuality = iota // Fix not available or invalid.
+	FixGPS                          // GPS fix.
+	FixDGPS                         // Differential GPS fix.
+	FixPPS                          // PPS fix.
+	FixRTK                          // Real time kinematic fix.
+	FixFloatRTK                     // Float RTK fix.
+	FixEstimated                    // Estimated (dead reckoning) fix.
+	FixManual                       // Manual input mode.
+	FixSimulation                   // Simulation mode.
+)
+
+var fixQualityNames = [...]string{
+	FixInvalid:    "invalid",
+	FixGPS:        "GPS",
+	FixDGPS:       "DGPS",
+	FixPPS:        "PPS",
+	FixRTK:        "RTK",
+	FixFloatRTK:   "float RTK",
+	FixEstimated:  "estimated",
+	FixManual:     "manual",
+	FixSimulation: "simulation",
+}
+
+func (q FixQuality) valid() bool {
+	return 0 <= q && int(q) < len(fixQualityNames)
+}
+
+func (q FixQuality) String() string {
+	if !q.valid() {
+		return "FixQuality(" + strconv.Itoa(int(q)) + ")"
+	}
+	return fixQualityNames[q]
+}
+
+// FixType is a GSA fix type. The zero value indicates
+// that the fix type was not given.
+type FixType int
+
+const (
+	FixNone FixType = iota + 1 // No fix.
+	Fix2D                      // 2D fix.
+	Fix3D                      // 3D fix.
+)
+
+func (t FixType) valid() bool {
+	return 0 <= t && t <= Fix3D
+}
+
+func (t FixType) String() string {
+	switch t {
+	case 0:
+		return "unknown"
+	case FixNone:
+		return "no fix"
+	case Fix2D:
+		return "2D"
+	case Fix3D:
+		return "3D"
+	}
+	return "FixType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// enum is a type with a restricted set of valid values.
+type enum interface {
+	valid() bool
+}
+
+var enumType = reflect.TypeOf((*enum)(nil)).Elem()
+
+// enumerated returns a method that applies m to fields of type typ
+// and checks that parsed and formatted values are valid if typ is
+// an enum type. Otherwise it returns m.
+func enumerated(m method, typ reflect.Type) method {
+	if m.span > 1 || !reflect.PtrTo(typ).Implements(enumType) {
+		return m
+	}
+	wrap := func(parse ParseFunc) ParseFunc {
+		if parse == nil {
+			return nil
+		}
+		return func(dst reflect.Value, field string) error {
+			err := parse(dst, field)
+			if err != nil {
+				return err
+			}
+			if !isValid(dst) {
+				return ErrEnum
+			}
+			return nil
+		}
+	}
+	e := method{parse: wrap(m.parse), borrow: wrap(m.borrow)}
+	if m.format != nil {
+		e.format = func(dst []byte, src reflect.Value) ([]byte, error) {
+			if !isValid(src) {
+				return dst, ErrEnum
+			}
+			return m.format(dst, src)
+		}
+	}
+	return e
+}
+
+// isValid returns whether the enum value held by v is valid.
+func isValid(v reflect.Value) bool {
+	if v.CanAddr() {
+		// Avoid allocating an interface value.
+		return v.Addr().Interface().(enum).valid()
+	}
+	return v.Interface().(enum).valid()
+}
+
+// Modes returns the FAA mode indicators held by the GNS Mode field, one
+// for each satellite system.
+func (m GNS) Modes() ([]FAAMode, error) {
+	modes := make([]FAAMode, len(m.Mode))
+	for i := range m.Mode {
+		modes[i] = FAAMode(m.Mode[i : i+1])
+		if !modes[i].valid() {
+			return nil, ErrEnum
+		}
+	}
+	return modes, nil
+}
@@ -0,0 +1,81 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var badEnumTests = []struct {
+	sentence string
+	field    string
+}{
+	{sentence: "$GPGGA,123519,4807.038,N,01131.000,W,9,2,3,4,M,5,M,,", field: "Quality"},
+	{sentence: "$GPGSA,X,3,,,,,,16,18,,22,24,,,3.6,2.1,2.2", field: "Mode"},
+	{sentence: "$GPGSA,A,4,,,,,,16,18,,22,24,,,3.6,2.1,2.2", field: "Fix"},
+	{sentence: "$GPRMC,123519,X,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W", field: "Status"},
+	{sentence: "$GPGLL,4916.45,N,12311.12,W,225444,A,Q", field: "Mode"},
+	{sentence: "$GPXTE,A,AV,0.67,L,N", field: "LockFlag"},
+}
+
+func TestEnums(t *testing.T) {
+	for _, test := range badEnumTests {
+		for _, parse := range []func(string) (interface{}, error){
+			Parse,
+			func(s string) (interface{}, error) { return ParseBytes([]byte(s)) },
+		} {
+			_, err := parse(test.sentence)
+			if !errors.Is(err, ErrEnum) {
+				t.Errorf("unexpected error for %q: got:%v want:%v", test.sentence, err, ErrEnum)
+				continue
+			}
+			var perr *ParseError
+			if errors.As(err, &perr) && perr.Field != test.field {
+				t.Errorf("unexpected error field for %q: got:%q want:%q", test.sentence, perr.Field, test.field)
+			}
+		}
+	}
+
+	_, err := Marshal(GGA{Type: "GPGGA", Quality: 9})
+	if !errors.Is(err, ErrEnum) {
+		t.Errorf("unexpected error marshaling invalid quality: got:%v want:%v", err, ErrEnum)
+	}
+
+	for _, test := range []struct {
+		val  interface{ String() string }
+		want string
+	}{
+		{val: StatusValid, want: "valid"},
+		{val: Status(""), want: "unknown"},
+		{val: Status("X"), want: `Status("X")`},
+		{val: FAAModeRTKFloat, want: "RTK float"},
+		{val: FAAMode("Q"), want: `FAAMode("Q")`},
+		{val: SelectionManual, want: "manual"},
+		{val: FixDGPS, want: "DGPS"},
+		{val: FixQuality(9), want: "FixQuality(9)"},
+		{val: Fix3D, want: "3D"},
+		{val: FixType(0), want: "unknown"},
+		{val: FixType(-1), want: "FixType(-1)"},
+	} {
+		if got := test.val.String(); got != test.want {
+			t.Errorf("unexpected string for %#v: got:%q want:%q", test.val, got, test.want)
+		}
+	}
+
+	modes, err := GNS{Mode: "ADN"}.Modes()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	wantModes := []FAAMode{FAAModeAutonomous, FAAModeDifferential, FAAModeNotValid}
+	if !reflect.DeepEqual(modes, wantModes) {
+		t.Errorf("unexpected modes: got:%v want:%v", modes, wantModes)
+	}
+	_, err = GNS{Mode: "AX"}.Modes()
+	if !errors.Is(err, ErrEnum) {
+		t.Errorf("unexpected error for invalid modes: got:%v want:%v", err, ErrEnum)
+	}
+}
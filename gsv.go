@@ -0,0 +1,99 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+// SkyView is the complete set of satellites in view reported by a talker
+// in a sequence of GSV sentences.
+type SkyView struct {
+	// Talker is the talker ID of the
+	// GSV sentences, for example "GP"
+	// for GPS or "GL" for GLONASS.
+	Talker string
+
+	// SatellitesInView is the number of
+	// satellites in view reported by the
+	// sequence.
+	SatellitesInView int
+
+	// Satellites holds the satellites of
+	// all the sentences of the sequence
+	// in order.
+	Satellites []GSVSatellite
+}
+
+// GSVAssembler assembles sequences of GSV sentences into SkyViews. Each
+// talker's sequence is assembled independently, so sentences from several
+// constellations, for example GP, GL, GA, GB and GN, may be interleaved.
+// The zero value is an empty GSVAssembler ready to use.
+type GSVAssembler struct {
+	pending map[string]*gsvSequence
+	lost    int
+}
+
+// gsvSequence is a partially assembled GSV sequence.
+type gsvSequence struct {
+	// total is the number of sentences in the
+	// sequence and next is the number of the
+	// next expected sentence.
+	total, next int
+
+	// broken is whether the sequence has been
+	// discarded and its remaining sentences
+	// are to be ignored.
+	broken bool
+
+	view SkyView
+}
+
+// Add adds the GSV sentence m to its talker's sequence. When m completes
+// the sequence, the assembled SkyView and true are returned. Sequences
+// that are incomplete, because a sentence was missed or received out of
+// order, are discarded and counted as lost.
+func (a *GSVAssembler) Add(m GSV) (view SkyView, ok bool) {
+	if a.pending == nil {
+		a.pending = make(map[string]*gsvSequence)
+	}
+	talker, _ := SplitType(m.Type)
+	s := a.pending[talker]
+	switch {
+	case m.MessageNumber == 1:
+		if s != nil && !s.broken {
+			// The previous sequence was not completed.
+			a.lost++
+		}
+		s = &gsvSequence{total: m.Messages, next: 1, view: SkyView{Talker: talker}}
+		a.pending[talker] = s
+	case s == nil:
+		// The start of the sequence was missed.
+		a.lost++
+		s = &gsvSequence{broken: true}
+		a.pending[talker] = s
+	}
+
+	if !s.broken && (m.Messages < 1 || m.Messages != s.total || m.MessageNumber != s.next) {
+		a.lost++
+		s.broken = true
+	}
+	if s.broken {
+		if m.MessageNumber >= m.Messages {
+			delete(a.pending, talker)
+		}
+		return SkyView{}, false
+	}
+
+	s.view.SatellitesInView = m.SatellitesInView
+	s.view.Satellites = append(s.view.Satellites, m.Satellites...)
+	s.next++
+	if s.next <= s.total {
+		return SkyView{}, false
+	}
+	delete(a.pending, talker)
+	return s.view, true
+}
+
+// Lost returns the number of GSV sequences discarded by a.
+func (a *GSVAssembler) Lost() int {
+	return a.lost
+}
@@ -0,0 +1,93 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"reflect"
+	"testing"
+)
+
+func gsv(typ string, n, i int, prns ...int) GSV {
+	m := GSV{Type: typ, Messages: n, MessageNumber: i, SatellitesInView: 6}
+	for _, prn := range prns {
+		m.Satellites = append(m.Satellites, GSVSatellite{PRN: prn})
+	}
+	return m
+}
+
+var gsvAssemblerTests = []struct {
+	in   GSV
+	want *SkyView
+	lost int
+}{
+	{in: gsv("GPGSV", 2, 1, 1, 2, 3, 4)},
+	{in: gsv("GLGSV", 2, 1, 65, 66, 67, 68)},
+	{in: gsv("GPGSV", 2, 2, 5, 6), want: &SkyView{Talker: "GP", SatellitesInView: 6, Satellites: []GSVSatellite{{PRN: 1}, {PRN: 2}, {PRN: 3}, {PRN: 4}, {PRN: 5}, {PRN: 6}}}},
+	{in: gsv("GLGSV", 2, 2, 69, 70), want: &SkyView{Talker: "GL", SatellitesInView: 6, Satellites: []GSVSatellite{{PRN: 65}, {PRN: 66}, {PRN: 67}, {PRN: 68}, {PRN: 69}, {PRN: 70}}}},
+
+	// Missed start.
+	{in: gsv("GAGSV", 2, 2, 5, 6), lost: 1},
+
+	// Missed middle sentence.
+	{in: gsv("GPGSV", 3, 1, 1, 2, 3, 4), lost: 1},
+	{in: gsv("GPGSV", 3, 3, 9), lost: 2},
+
+	// Restarted sequence.
+	{in: gsv("GBGSV", 2, 1, 1, 2, 3, 4), lost: 2},
+	{in: gsv("GBGSV", 2, 1, 1, 2, 3, 4), lost: 3},
+	{in: gsv("GBGSV", 2, 2, 5, 6), want: &SkyView{Talker: "GB", SatellitesInView: 6, Satellites: []GSVSatellite{{PRN: 1}, {PRN: 2}, {PRN: 3}, {PRN: 4}, {PRN: 5}, {PRN: 6}}}, lost: 3},
+
+	// Inconsistent sentence count.
+	{in: gsv("GNGSV", 2, 1, 1, 2, 3, 4), lost: 3},
+	{in: gsv("GNGSV", 3, 2, 5, 6, 7, 8), lost: 4},
+	{in: gsv("GNGSV", 3, 3, 9), lost: 4},
+
+	// Single sentence sequence.
+	{in: gsv("GNGSV", 1, 1, 1, 2), want: &SkyView{Talker: "GN", SatellitesInView: 6, Satellites: []GSVSatellite{{PRN: 1}, {PRN: 2}}}, lost: 4},
+}
+
+func TestGSVAssembler(t *testing.T) {
+	var a GSVAssembler
+	for i, test := range gsvAssemblerTests {
+		got, ok := a.Add(test.in)
+		if ok != (test.want != nil) {
+			t.Errorf("unexpected completion for sentence %d: got:%t want:%t", i, ok, test.want != nil)
+		}
+		if ok && test.want != nil && !reflect.DeepEqual(got, *test.want) {
+			t.Errorf("unexpected sky view for sentence %d:\ngot: %+v\nwant:%+v", i, got, *test.want)
+		}
+		if a.Lost() != test.lost {
+			t.Errorf("unexpected lost count after sentence %d: got:%d want:%d", i, a.Lost(), test.lost)
+		}
+	}
+}
+
+func TestGSVAssemblerParsed(t *testing.T) {
+	var a GSVAssembler
+	var (
+		got SkyView
+		ok  bool
+	)
+	for _, sentence := range []string{
+		"$GPGSV,2,1,08,01,40,083,46,02,17,308,41,12,07,344,39,14,22,228,45*75",
+		"$GPGSV,2,2,08,15,07,101,,18,08,040,33,21,52,231,40,22,41,057,38*7B",
+	} {
+		var m GSV
+		err := ParseTo(&m, sentence)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", sentence, err)
+		}
+		got, ok = a.Add(m)
+	}
+	if !ok {
+		t.Fatal("sequence not completed")
+	}
+	if got.Talker != "GP" || got.SatellitesInView != 8 || len(got.Satellites) != 8 {
+		t.Errorf("unexpected sky view: %+v", got)
+	}
+	if got.Satellites[4].PRN != 15 {
+		t.Errorf("unexpected satellite order: got PRN %d at 4, want 15", got.Satellites[4].PRN)
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package iec450 implements the IEC 61162-450 UDP multicast transport
+// for NMEA 0183 sentences.
+//
+// Each datagram starts with the "UdPbC\x00" header and holds one or more
+// sentences, each preceded by a tag block holding at least the source
+// identifier (s) and line count (n) of the sentence and terminated by
+// CRLF. Line counts run from 1 to 999 for each source and are used to
+// detect lost sentences.
+package iec450
+
+import (
+	"bytes"
+	"errors"
+	"net"
+
+	"github.com/kortschak/nmea"
+)
+
+// Header is the header of IEC 61162-450 sentence datagrams.
+const Header = "UdPbC\x00"
+
+// MaxLineCount is the largest line count. Line counts wrap
+// from MaxLineCount to 1.
+const MaxLineCount = 999
+
+// MaxDatagramSize is the maximum size of a datagram.
+const MaxDatagramSize = 1472
+
+var (
+	ErrHeader   = errors.New("iec450: invalid datagram header")
+	ErrTooLarge = errors.New("iec450: datagram too large")
+)
+
+// Message is a sentence received in an IEC 61162-450 datagram.
+type Message struct {
+	// Addr is the address of the sender.
+	Addr net.Addr
+
+	// TagBlock is the tag block preceding
+	// the sentence.
+	TagBlock nmea.TagBlock
+
+	// Sentence is the raw sentence text.
+	Sentence string
+
+	// Value and Err are the results of
+	// parsing Sentence. If the tag block
+	// is invalid, Err holds the tag block
+	// error and Value is nil.
+	Value interface{}
+	Err   error
+
+	// Lost is the number of sentences from the
+	// same source that were lost immediately
+	// before this one, according to their line
+	// counts.
+	Lost int
+}
+
+// Receiver receives sentences from an IEC 61162-450 transmission group.
+type Receiver struct {
+	// Registry is the registry used to parse received
+	// sentences and their tag blocks. If Registry is nil,
+	// nmea.Parse and nmea.ParseTagBlock are used.
+	Registry *nmea.Registry
+
+	conn net.PacketConn
+
+	// lines holds the line count of the last
+	// sentence from each source.
+	lines map[string]int
+	// lost is the total number of lost sentences.
+	lost int
+
+	buf     []byte
+	pending []Message
+}
+
+// Listen returns a Receiver that has joined the multicast group on the
+// network interface ifi. If ifi is nil, the system-assigned multicast
+// interface is used.
+func Listen(ifi *net.Interface, group *net.UDPAddr) (*Receiver, error) {
+	conn, err := net.ListenMulticastUDP("udp4", ifi, group)
+	if err != nil {
+		return nil, err
+	}
+	return NewReceiver(conn), nil
+}
+
+// NewReceiver returns a Receiver that reads datagrams from conn.
+func NewReceiver(conn net.PacketConn) *Receiver {
+	return &Receiver{
+		conn:  conn,
+		lines: make(map[string]int),
+		buf:   make([]byte, 65536),
+	}
+}
+
+// Receive returns the next sentence received by r. Errors in parsing the
+// sentence are held in the returned Message. Sentences with invalid tag
+// blocks are not parsed or sequenced and are returned with the tag block
+// error in the Message, without affecting other sentences in the same
+// datagram. Datagrams without a valid header are discarded and reported
+// by a returned error.
+func (r *Receiver) Receive() (Message, error) {
+	for len(r.pending) == 0 {
+		n, addr, err := r.conn.ReadFrom(r.buf)
+		if err != nil {
+			return Message{}, err
+		}
+		err = r.datagram(addr, r.buf[:n])
+		if err != nil {
+			return Message{}, err
+		}
+	}
+	m := r.pending[0]
+	r.pending = r.pending[1:]
+	return m, nil
+}
+
+// datagram queues the sentences held in the datagram b from addr.
+func (r *Receiver) datagram(addr net.Addr, b []byte) error {
+	if !bytes.HasPrefix(b, []byte(Header)) {
+		return ErrHeader
+	}
+	b = b[len(Header):]
+	for len(b) != 0 {
+		var line []byte
+		if i := bytes.IndexByte(b, '\n'); i < 0 {
+			line, b = b, nil
+		} else {
+			line, b = b[:i], b[i+1:]
+		}
+		line = bytes.TrimRight(line, "\r")
+		if len(line) == 0 {
+			continue
+		}
+		var (
+			tb       nmea.TagBlock
+			sentence string
+			err      error
+		)
+		if r.Registry == nil {
+			tb, sentence, err = nmea.ParseTagBlock(string(line))
+		} else {
+			tb, sentence, err = r.Registry.ParseTagBlock(string(line))
+		}
+		if err != nil {
+			// Discard only this sentence.
+			r.pending = append(r.pending, Message{Addr: addr, Sentence: sentence, Err: err})
+			continue
+		}
+		m := Message{Addr: addr, TagBlock: tb, Sentence: sentence}
+		if r.Registry == nil {
+			m.Value, m.Err = nmea.Parse(sentence)
+		} else {
+			m.Value, m.Err = r.Registry.Parse(sentence)
+		}
+		m.Lost = r.sequence(tb)
+		r.pending = append(r.pending, m)
+	}
+	return nil
+}
+
+// sequence records the line count of tb for its source and returns
+// the number of sentences lost since the previous line count.
+func (r *Receiver) sequence(tb nmea.TagBlock) int {
+	if tb.LineCount < 1 || MaxLineCount < tb.LineCount {
+		return 0
+	}
+	last, ok := r.lines[tb.Source]
+	r.lines[tb.Source] = tb.LineCount
+	if !ok {
+		return 0
+	}
+	lost := (tb.LineCount - last + MaxLineCount) % MaxLineCount
+	if lost == 0 {
+		// A repeated line count.
+		return 0
+	}
+	lost--
+	r.lost += lost
+	return lost
+}
+
+// Lost returns the total number of sentences detected as lost by r.
+func (r *Receiver) Lost() int {
+	return r.lost
+}
+
+// Close closes the Receiver's connection.
+func (r *Receiver) Close() error {
+	return r.conn.Close()
+}
+
+// Sender sends sentences to an IEC 61162-450 transmission group.
+type Sender struct {
+	// Source is the source identifier written
+	// in the tag block of each sentence.
+	Source string
+
+	conn net.Conn
+	line int
+	buf  []byte
+}
+
+// Dial returns a Sender that sends sentences to the multicast group with
+// the given source identifier.
+func Dial(group *net.UDPAddr, source string) (*Sender, error) {
+	conn, err := net.DialUDP("udp4", nil, group)
+	if err != nil {
+		return nil, err
+	}
+	return NewSender(conn, source), nil
+}
+
+// NewSender returns a Sender that writes datagrams to conn with the
+// given source identifier.
+func NewSender(conn net.Conn, source string) *Sender {
+	return &Sender{Source: source, conn: conn}
+}
+
+// Send sends the sentences encoding values in a single datagram. Values
+// are encoded by nmea.AppendSentence, except for nmea.Sentence values,
+// which are sent as returned by their String method.
+func (s *Sender) Send(values ...interface{}) error {
+	b := append(s.buf[:0], Header...)
+	line := s.line
+	for _, v := range values {
+		line = line%MaxLineCount + 1
+		var err error
+		b, err = nmea.AppendTagBlock(b, nmea.TagBlock{Source: s.Source, LineCount: line})
+		if err != nil {
+			return err
+		}
+		if sentence, ok := v.(nmea.Sentence); ok {
+			b = append(b, sentence.String()...)
+		} else {
+			b, err = nmea.AppendSentence(b, v)
+			if err != nil {
+				return err
+			}
+		}
+		b = append(b, "\r\n"...)
+	}
+	s.buf = b
+	if len(b) > MaxDatagramSize {
+		return ErrTooLarge
+	}
+	_, err := s.conn.Write(b)
+	if err != nil {
+		return err
+	}
+	s.line = line
+	return nil
+}
+
+// Close closes the Sender's connection.
+func (s *Sender) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,187 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iec450
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kortschak/nmea"
+)
+
+func TestMulticast(t *testing.T) {
+	group := &net.UDPAddr{IP: net.IPv4(239, 192, 0, 1)}
+	r, err := Listen(nil, group)
+	if err != nil {
+		t.Skipf("multicast unavailable: %v", err)
+	}
+	defer r.Close()
+	group.Port = r.conn.LocalAddr().(*net.UDPAddr).Port
+
+	s, err := Dial(group, "GP0001")
+	if err != nil {
+		t.Skipf("multicast unavailable: %v", err)
+	}
+	defer s.Close()
+
+	hdt := nmea.HDT{Type: "GPHDT", Heading: 274.07}
+	unknown, err := nmea.ParseSentence("$IIXDR,C,19.5,C,AIR*07")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = s.Send(hdt, unknown)
+	if err != nil {
+		t.Skipf("multicast unavailable: %v", err)
+	}
+	// Simulate the loss of two sentences.
+	s.line += 2
+	err = s.Send(hdt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = r.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed := hdt
+	parsed.Checksum = 0x03
+	want := []struct {
+		tb       nmea.TagBlock
+		sentence string
+		value    interface{}
+		err      error
+		lost     int
+	}{
+		{
+			tb:       nmea.TagBlock{Source: "GP0001", LineCount: 1},
+			sentence: "$GPHDT,274.07,T*03",
+			value:    parsed,
+		},
+		{
+			tb:       nmea.TagBlock{Source: "GP0001", LineCount: 2},
+			sentence: "$IIXDR,C,19.5,C,AIR*07",
+			err:      nmea.ErrNotRegistered,
+		},
+		{
+			tb:       nmea.TagBlock{Source: "GP0001", LineCount: 5},
+			sentence: "$GPHDT,274.07,T*03",
+			value:    parsed,
+			lost:     2,
+		},
+	}
+	for i, w := range want {
+		m, err := r.Receive()
+		if err != nil {
+			if err, ok := err.(net.Error); ok && err.Timeout() && i == 0 {
+				t.Skipf("multicast unavailable: %v", err)
+			}
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m.TagBlock != w.tb {
+			t.Errorf("unexpected tag block for message %d:\ngot: %#v\nwant:%#v", i, m.TagBlock, w.tb)
+		}
+		if m.Sentence != w.sentence {
+			t.Errorf("unexpected sentence for message %d: got:%q want:%q", i, m.Sentence, w.sentence)
+		}
+		if w.value != nil && m.Value != w.value {
+			t.Errorf("unexpected value for message %d:\ngot: %#v\nwant:%#v", i, m.Value, w.value)
+		}
+		if m.Err != w.err {
+			t.Errorf("unexpected error for message %d: got:%v want:%v", i, m.Err, w.err)
+		}
+		if m.Lost != w.lost {
+			t.Errorf("unexpected lost count for message %d: got:%d want:%d", i, m.Lost, w.lost)
+		}
+	}
+	if r.Lost() != 2 {
+		t.Errorf("unexpected total lost count: got:%d want:2", r.Lost())
+	}
+}
+
+func TestDatagram(t *testing.T) {
+	r := NewReceiver(nil)
+	err := r.datagram(nil, []byte("UdPbX\x00\\s:GP0001,n:1*2D\\$GPHDT,274.07,T*03\r\n"))
+	if err != ErrHeader {
+		t.Errorf("unexpected error for invalid header: got:%v want:%v", err, ErrHeader)
+	}
+
+	for _, n := range []int{998, 999, 1, 1, 3} {
+		tb := nmea.TagBlock{Source: "GP0001", LineCount: n}
+		b, err := nmea.AppendTagBlock([]byte(Header), tb)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b = append(b, "$GPHDT,274.07,T*03\r\n"...)
+		err = r.datagram(nil, b)
+		if err != nil {
+			t.Errorf("unexpected error for line count %d: %v", n, err)
+		}
+	}
+	var lost []int
+	for _, m := range r.pending {
+		lost = append(lost, m.Lost)
+	}
+	want := []int{0, 0, 0, 0, 1}
+	for i := range want {
+		if lost[i] != want[i] {
+			t.Errorf("unexpected lost counts: got:%v want:%v", lost, want)
+			break
+		}
+	}
+
+	r.pending = nil
+	err = r.datagram(nil, []byte(Header+
+		"\\s:GP0001,n:4*13\\$GPHDT,274.07,T*03\r\n"+
+		"\\s:GP0001,n:5*00\\$GPHDT,274.07,T*03\r\n"+
+		"\\s:GP0001,n:6*11\\$GPHDT,274.07,T*03\r\n"))
+	if err != nil {
+		t.Errorf("unexpected error for datagram with invalid tag block: %v", err)
+	}
+	if len(r.pending) != 3 {
+		t.Fatalf("unexpected number of messages: got:%d want:3", len(r.pending))
+	}
+	for i, m := range r.pending {
+		if i == 1 {
+			if !errors.Is(m.Err, nmea.ErrChecksum) || m.Value != nil {
+				t.Errorf("unexpected result for invalid tag block: got:%v %v", m.Value, m.Err)
+			}
+			continue
+		}
+		if m.Err != nil {
+			t.Errorf("unexpected error for message %d: %v", i, m.Err)
+		}
+	}
+	if lost := r.pending[2].Lost; lost != 1 {
+		t.Errorf("unexpected lost count after invalid tag block: got:%d want:1", lost)
+	}
+}
+
+func TestDatagramChecksumIgnore(t *testing.T) {
+	reg := nmea.NewDefaultRegistry()
+	reg.SetParseOptions(nmea.ParseOptions{Checksum: nmea.ChecksumIgnore})
+	r := NewReceiver(nil)
+	r.Registry = reg
+	err := r.datagram(nil, []byte(Header+"\\s:a*00\\$GPHDT,1,T*2A\r\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(r.pending) != 1 {
+		t.Fatalf("unexpected number of messages: got:%d want:1", len(r.pending))
+	}
+	m := r.pending[0]
+	if m.Err != nil {
+		t.Errorf("unexpected error for ignored tag block checksum: %v", m.Err)
+	}
+	if m.TagBlock.Source != "a" {
+		t.Errorf("unexpected tag block source: got:%q want:%q", m.TagBlock.Source, "a")
+	}
+	want := nmea.HDT{Type: "GPHDT", Heading: 1, Checksum: 0x2a}
+	if m.Value != want {
+		t.Errorf("unexpected value: got:%#v want:%#v", m.Value, want)
+	}
+}
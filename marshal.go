@@ -0,0 +1,321 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Marshal returns the NMEA 0183 sentence encoding of src. The fields of
+// src are written according to the methods specified in their "nmea"
+// field tags, and the sentence is terminated with a checksum. Untagged
+// fields are written as empty sentence fields. The returned sentence
+// does not include a line terminator.
+//
+// If src implements Marshaler, the sentence is formed from the fields
+// returned by its MarshalNMEA method. Otherwise, the concrete value of
+// src must be a struct or a pointer to a struct. The Type field of src is
+// used as the sentence type if it is a non-empty string, otherwise the
+// literal type in the Type field's tag is used.
+func Marshal(src interface{}) (string, error) {
+	b, err := AppendSentence(nil, src)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// AppendSentence appends the NMEA 0183 sentence encoding of src to dst
+// and returns the extended buffer. See Marshal for details of the
+// encoding.
+func AppendSentence(dst []byte, src interface{}) ([]byte, error) {
+	if m, ok := src.(Marshaler); ok {
+		return appendMarshaler(dst, m)
+	}
+	rv := reflect.ValueOf(src)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return dst, ErrNotStruct
+	}
+	p := planFor(rv.Type())
+	if p.err != nil {
+		return dst, p.err
+	}
+	typ, err := p.typeOf(rv)
+	if err != nil {
+		return dst, err
+	}
+
+	start := len(dst)
+	dst = append(dst, sigilFor(typ))
+	dst = append(dst, typ...)
+	e := encoder{dst: dst}
+	err = e.fields(rv, p.fields, 0)
+	if err != nil {
+		return dst[:start], err
+	}
+	e.seek(p.width - 1)
+	dst = e.dst
+	sum := checksum(string(dst[start+1:]))
+	dst = append(dst, '*', hexDigits[sum>>4], hexDigits[sum&0xf])
+	return dst, nil
+}
+
+// appendMarshaler appends the sentence formed from the fields returned
+// by m to dst.
+func appendMarshaler(dst []byte, m Marshaler) ([]byte, error) {
+	fields, err := m.MarshalNMEA()
+	if err != nil {
+		return dst, err
+	}
+	if len(fields) == 0 || fields[0] == "" {
+		return dst, ErrNMEAType
+	}
+	for _, f := range fields {
+		if strings.ContainsAny(f, reserved) {
+			return dst, ErrReserved
+		}
+	}
+
+	start := len(dst)
+	dst = append(dst, sigilFor(fields[0]))
+	for i, f := range fields {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, f...)
+	}
+	sum := checksum(string(dst[start+1:]))
+	return append(dst, '*', hexDigits[sum>>4], hexDigits[sum&0xf]), nil
+}
+
+// encoder writes sentence fields into a buffer.
+type encoder struct {
+	dst []byte
+	// pos is the position of the last
+	// sentence field started in dst.
+	pos int
+}
+
+// seek starts empty sentence fields until the field at pos is reached.
+func (e *encoder) seek(pos int) {
+	for ; e.pos < pos; e.pos++ {
+		e.dst = append(e.dst, ',')
+	}
+}
+
+// fields writes the fields of the struct rv described by plans, starting
+// at the sentence field position base.
+func (e *encoder) fields(rv reflect.Value, plans []fieldPlan, base int) error {
+	for i := range plans {
+		f := &plans[i]
+		var err error
+		if f.hemi != 0 && isEastWest(rv.Field(plans[f.hemi-1].index)) {
+			e.seek(base + f.pos)
+			e.dst, err = f.lonFormat(e.dst, rv.Field(f.index))
+		} else {
+			err = e.value(f, rv.Field(f.index), base+f.pos)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isEastWest returns whether v, or the value v points to, holds a
+// longitude hemisphere.
+func isEastWest(v reflect.Value) bool {
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.String {
+		return false
+	}
+	hemi := v.String()
+	return hemi == "E" || hemi == "W"
+}
+
+// value writes src at the sentence field position pos.
+func (e *encoder) value(f *fieldPlan, src reflect.Value, pos int) error {
+	switch f.kind {
+	case structField:
+		return e.fields(src, f.fields, pos)
+
+	case arrayField, sliceField:
+		if !f.rest && src.Len() > f.repeat {
+			return ErrRepeat
+		}
+		for i := 0; i < src.Len(); i++ {
+			err := e.value(f.elem, src.Index(i), pos+i*f.elem.width)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if f.format == nil {
+		return ErrUnknownMethod
+	}
+	e.seek(pos)
+	var err error
+	e.dst, err = f.format(e.dst, src)
+	e.pos += f.width - 1
+	return err
+}
+
+// typeOf returns the NMEA sentence type of the struct held by rv.
+func (p *plan) typeOf(rv reflect.Value) (string, error) {
+	var typ string
+	if p.storeType {
+		typ = rv.Field(0).String()
+	}
+	if typ == "" {
+		typ = p.literal
+	}
+	if !p.matches(typ) {
+		return "", ErrNMEAType
+	}
+	return typ, nil
+}
+
+// encapsulated is the set of sentence formatters that are sent with
+// the '!' encapsulation sigil rather than '$'.
+var encapsulated = map[string]bool{
+	"ABM": true,
+	"BBM": true,
+	"VDM": true,
+	"VDO": true,
+}
+
+// sigilFor returns the start sigil for sentences of the given type.
+func sigilFor(typ string) byte {
+	if len(typ) >= 3 && encapsulated[typ[len(typ)-3:]] {
+		return '!'
+	}
+	return '$'
+}
+
+const hexDigits = "0123456789ABCDEF"
+
+func appendNumber(dst []byte, src reflect.Value) ([]byte, error) {
+	switch kind := src.Kind(); kind {
+	default:
+		return dst, ErrType
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(dst, src.Float(), 'f', -1, sizeOfFloat[kind]), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(dst, src.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.AppendUint(dst, src.Uint(), 10), nil
+	}
+}
+
+var sizeOfFloat = map[reflect.Kind]int{
+	reflect.Float32: 32,
+	reflect.Float64: 64,
+}
+
+// reserved holds the characters that may not appear within a sentence field.
+const reserved = "\r\n$*,!\\^~"
+
+func appendString(dst []byte, src reflect.Value) ([]byte, error) {
+	var s string
+	switch src.Kind() {
+	default:
+		return dst, ErrType
+	case reflect.String:
+		s = src.String()
+	case reflect.Slice:
+		if src.Type().Elem().Kind() != reflect.Uint8 {
+			return dst, ErrType
+		}
+		s = string(src.Bytes())
+	}
+	if strings.ContainsAny(s, reserved) {
+		return dst, ErrReserved
+	}
+	return append(dst, s...), nil
+}
+
+func appendLatLon(dst []byte, src reflect.Value) ([]byte, error) {
+	switch src.Kind() {
+	default:
+		return dst, ErrType
+	case reflect.Float64, reflect.Float32:
+		return appendDegrees(dst, src.Float(), 2), nil
+	}
+}
+
+// appendLongitude is the format function for "latlon" fields that are
+// followed by an east or west hemisphere.
+func appendLongitude(dst []byte, src reflect.Value) ([]byte, error) {
+	switch src.Kind() {
+	default:
+		return dst, ErrType
+	case reflect.Float64, reflect.Float32:
+		return appendDegrees(dst, src.Float(), 3), nil
+	}
+}
+
+// appendDegrees appends the NMEA [d]ddmm.mmmmm representation of the
+// decimal degrees value v with at least degDigits digits of degrees.
+func appendDegrees(dst []byte, v float64, degDigits int) []byte {
+	deg, frac := math.Modf(math.Abs(v))
+	// Round to the precision of a typical NMEA
+	// receiver to avoid float noise in the output.
+	min := math.Round(frac*60*1e5) / 1e5
+	if min >= 60 {
+		deg++
+		min -= 60
+	}
+	if v < 0 {
+		dst = append(dst, '-')
+	}
+	var buf [32]byte
+	val := strconv.AppendFloat(buf[:0], deg*100+min, 'f', -1, 64)
+	intDigits := bytes.IndexByte(val, '.')
+	if intDigits < 0 {
+		intDigits = len(val)
+	}
+	for ; intDigits < degDigits+2; intDigits++ {
+		dst = append(dst, '0')
+	}
+	return append(dst, val...)
+}
+
+func appendDate(dst []byte, src reflect.Value) ([]byte, error) {
+	if src.Type() != timeType {
+		return dst, ErrType
+	}
+	t := src.Interface().(time.Time)
+	if t.IsZero() {
+		return dst, nil
+	}
+	return t.In(time.UTC).AppendFormat(dst, "020106"), nil
+}
+
+func appendTime(dst []byte, src reflect.Value) ([]byte, error) {
+	switch src.Type() {
+	case timeOfDayType:
+		return appendTimeOfDay(dst, TimeOfDay(src.Int()))
+	case timeType:
+		t := src.Interface().(time.Time)
+		if t.IsZero() {
+			return dst, nil
+		}
+		return appendTimeOfDay(dst, timeOfDay(t))
+	default:
+		return dst, ErrType
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var marshalTests = []struct {
+	src     interface{}
+	want    string
+	wantErr error
+}{
+	{
+		src: BOD{
+			Type:        "GPBOD",
+			True:        97,
+			Magnetic:    103.2,
+			Destination: "POINTB",
+			Start:       "POINTA",
+		},
+		want: "$GPBOD,97,T,103.2,M,POINTB,POINTA*64",
+	},
+	{
+		src: &GGA{
+			Type:      "GPGGA",
+			Timestamp: time.Date(0, 1, 1, 17, 8, 34, 0, time.UTC),
+			Latitude:  41.41493833333334, NorthSouth: "N",
+			Longitude: 81.86139666666665, EastWest: "W",
+			Quality:    1,
+			Satellites: 5,
+			HDOP:       1.5,
+			Altitude:   280.2, AltitudeUnit: "M",
+			Separation: -34, SeparationUnit: "M",
+		},
+		want: "$GPGGA,170834,4124.8963,N,08151.6838,W,1,5,1.5,280.2,M,-34,M,0,*6B",
+	},
+	{
+		src: WPL{
+			Type:     "GPWPL",
+			Latitude: 48.1173, NorthSouth: "N",
+			Longitude: 11.516666666666667, EastWest: "E",
+			Waypoint: "WPTNME",
+		},
+		want: "$GPWPL,4807.038,N,01131,E,WPTNME*42",
+	},
+	{
+		src: WPL{
+			Type:     "GPWPL",
+			Latitude: 0.5, NorthSouth: "S",
+			Longitude: 1.5, EastWest: "W",
+			Waypoint: "NULL",
+		},
+		want: "$GPWPL,0030,S,00130,W,NULL*5E",
+	},
+	{
+		src: func() optionalGGA {
+			lat, ns := 48.1173, "N"
+			lon, ew := 11.5, "E"
+			return optionalGGA{
+				Type:     "GPGGA",
+				Latitude: &lat, NorthSouth: &ns,
+				Longitude: &lon, EastWest: &ew,
+			}
+		}(),
+		want: "$GPGGA,,4807.038,N,01130,E,,,,,,,,*5C",
+	},
+	{
+		src: TRF{
+			Type: "GPTRF",
+			Time: time.Date(0, 1, 1, 05, 32, 20, 30e6, time.UTC),
+			Date: time.Date(1997, 11, 05, 0, 0, 0, 0, time.UTC),
+		},
+		want: "$GPTRF,053220.03,051197,0000,,0000,,0,0,0,0,*5B",
+	},
+	{
+		src:  RMM{MapDatum: "NAD27 Canada"},
+		want: "$PGRMM,NAD27 Canada*2F",
+	},
+	{
+		src: VDMVDO{
+			Type:           "AIVDM",
+			Fragments:      1,
+			FragmentNumber: 1,
+			ChannelCode:    "B",
+			Data:           "177KQJ5000G?tO`K>RA1wUbN0TKH",
+		},
+		want: "!AIVDM,1,1,,B,177KQJ5000G?tO`K>RA1wUbN0TKH,0*5C",
+	},
+	{
+		src:     GGA{},
+		wantErr: ErrNMEAType,
+	},
+	{
+		src:     GGA{Type: "GPRMC"},
+		wantErr: ErrNMEAType,
+	},
+	{
+		src:     RMM{MapDatum: "NAD27,Canada"},
+		wantErr: ErrReserved,
+	},
+	{
+		src:     1,
+		wantErr: ErrNotStruct,
+	},
+	{
+		src:     struct{ A int }{},
+		wantErr: ErrMissingType,
+	},
+}
+
+func TestMarshal(t *testing.T) {
+	for _, test := range marshalTests {
+		got, err := Marshal(test.src)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("unexpected error for %#v: got:%v want:%v", test.src, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Errorf("unexpected result:\ngot: %q\nwant:%q", got, test.want)
+		}
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	for _, test := range parseTests {
+		sentence, err := Marshal(test.want)
+		if err != nil {
+			t.Errorf("unexpected error marshaling %#v: %v", test.want, err)
+			continue
+		}
+		dst := reflect.New(reflect.TypeOf(test.want).Elem()).Interface()
+		err = ParseTo(dst, sentence)
+		if errors.Is(err, ErrTime) && strings.Count(sentence, ",") > strings.Count(test.sentence, ",") {
+			// Time fields missing from truncated sentences are
+			// written as empty fields, which are not valid times.
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %v", sentence, err)
+			continue
+		}
+		got, err := Marshal(dst)
+		if err != nil {
+			t.Errorf("unexpected error marshaling %#v: %v", dst, err)
+			continue
+		}
+		if got != sentence {
+			t.Errorf("unexpected round trip result:\ngot: %q\nwant:%q", got, sentence)
+		}
+	}
+}
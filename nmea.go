@@ -6,14 +6,16 @@ package nmea
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unsafe"
 )
 
 var (
@@ -29,238 +31,287 @@ var (
 	ErrTypeSyntax    = errors.New("nmea: bad syntax for type match")
 	ErrNotRegistered = errors.New("nmea: sentence type not registered")
 	ErrBadBinary     = errors.New("nmea: invalid binary data encoding")
+	ErrReserved      = errors.New("nmea: reserved character in field")
+	ErrUnknownMethod = errors.New("nmea: unknown field method")
+	ErrHemisphere    = errors.New("nmea: invalid hemisphere")
+	ErrTagSyntax     = errors.New("nmea: bad syntax for field tag")
+	ErrRepeat        = errors.New("nmea: invalid repeat count")
+	ErrLateRest      = errors.New("nmea: field after rest field")
+	ErrPosition      = errors.New("nmea: overlapping field position")
+	ErrTime          = errors.New("nmea: invalid time of day")
+	ErrEnum          = errors.New("nmea: invalid enumerated value")
+	ErrTagBlock      = errors.New("nmea: invalid tag block")
+	ErrBadChecksum   = errors.New("nmea: invalid checksum syntax")
+	ErrNoChecksum    = errors.New("nmea: missing checksum")
 )
 
 // ParseTo parses a raw NMEA 0183 sentence and fills the fields of dst with the
 // data contained within the sentence. If the sentence has a checksum it is
-// compared with the checksum of the sentence's bytes.
+// compared with the checksum of the sentence's bytes and a mismatch is
+// reported with a *ChecksumError after dst has been filled. Trailing white
+// space following the sentence is ignored.
 //
-// The concrete value of dst must be a pointer to a struct.
+// The concrete value of dst must be a pointer to a struct with valid
+// "nmea" tags. If the tags are not valid, the error returned by Validate
+// is returned before the sentence is parsed.
 func ParseTo(dst interface{}, sentence string) error {
-	switch {
-	case len(sentence) < 6: // [!$].{5}
-		return ErrTooShort
-	case sentence[0] != '$' && sentence[0] != '!':
-		return ErrNoSigil
-	}
-	sentence = sentence[1:]
+	return parseTo(dst, sentence, ParseOptions{}, false)
+}
 
-	var sum, wantSum int64
-	if sumMarkIdx := strings.Index(sentence, "*"); sumMarkIdx != -1 {
-		var err error
-		wantSum, err = strconv.ParseInt(sentence[sumMarkIdx+1:], 16, 8)
-		if err != nil {
-			return err
-		}
-		sentence = sentence[:sumMarkIdx]
-		sum = checksum(sentence)
+// ParseToBytes is like ParseTo but parses a sentence held in a byte slice.
+// The fields of the sentence are parsed in place, so when dst is reused
+// for successive sentences, parsing with the built-in methods does not
+// allocate unless the value of a string field changes. The sentence is
+// not retained after ParseToBytes returns.
+//
+// Byte slice fields filled by the "string" method reuse the backing array
+// they already hold, so a slice retained from an earlier parse into dst is
+// overwritten by the next call. Callers that keep such a slice must copy it
+// or set the field to nil before parsing again.
+func ParseToBytes(dst interface{}, sentence []byte) error {
+	return parseTo(dst, unsafeString(sentence), ParseOptions{}, true)
+}
+
+// parseTo implements ParseTo and ParseToBytes using the options in opts.
+// If borrowed is true, sentence must not be retained after parseTo returns.
+func parseTo(dst interface{}, sentence string, opts ParseOptions, borrowed bool) error {
+	f, err := splitSentence(sentence, opts)
+	if err != nil {
+		return err
+	}
+	err = f.verify(opts.Checksum)
+	if err != nil {
+		return err
 	}
 
 	rv := reflect.ValueOf(dst)
 	if rv.Kind() != reflect.Ptr {
 		return ErrNotPointer
 	}
+	fields := getFields()
+	defer putFields(fields)
+	if u, ok := dst.(Unmarshaler); ok {
+		*fields = splitFields((*fields)[:0], f.body)
+		err = unmarshal(u, *fields, borrowed)
+		return f.report(opts.Checksum, err)
+	}
+
 	rv = rv.Elem()
 	if rv.Kind() != reflect.Struct {
 		return ErrNotStruct
 	}
+	p := planFor(rv.Type())
+	if p.err != nil {
+		return p.err
+	}
+
+	*fields = splitFields((*fields)[:0], f.body)
+	err = p.parse(rv, *fields, int64(f.want), borrowed)
+	return f.report(opts.Checksum, err)
+}
+
+// frame is a sentence split into its parts.
+type frame struct {
+	// start is the start delimiter.
+	start byte
+	// body is the text between the start
+	// delimiter and the checksum.
+	body string
+
+	// hasSum is whether the sentence has a
+	// checksum. If it does, sum is the
+	// computed checksum and want is the
+	// checksum given in the sentence.
+	hasSum    bool
+	sum, want byte
+}
 
-	err := parseTo(rv, strings.Split(sentence, ","), wantSum)
-	if sum != wantSum {
-		return ErrChecksum
+// splitSentence checks any tag blocks and the length and sigil of sentence
+// and returns the parts of the sentence. Returned errors do not retain
+// sentence.
+func splitSentence(sentence string, opts ParseOptions) (frame, error) {
+	sentence, err := skipTagBlocks(sentence, opts.Checksum)
+	if err != nil {
+		return frame{}, err
+	}
+	if !opts.StrictSpace {
+		sentence = strings.TrimRight(sentence, " \t\r\n")
+	}
+	switch {
+	case len(sentence) < 6: // [!$].{5}
+		return frame{}, ErrTooShort
+	case sentence[0] != '$' && sentence[0] != '!':
+		return frame{}, ErrNoSigil
 	}
-	return err
+	f := frame{start: sentence[0], body: sentence[1:]}
+
+	if sumMarkIdx := strings.IndexByte(f.body, '*'); sumMarkIdx != -1 {
+		var ok bool
+		f.want, ok = parseHexByte(f.body[sumMarkIdx+1:], !opts.StrictHex)
+		if !ok {
+			return frame{}, ErrBadChecksum
+		}
+		f.body = f.body[:sumMarkIdx]
+		f.sum = byte(checksum(f.body))
+		f.hasSum = true
+	}
+	return f, nil
+}
+
+// splitFields appends the comma-separated fields of s to dst.
+func splitFields(dst []string, s string) []string {
+	for {
+		i := strings.IndexByte(s, ',')
+		if i < 0 {
+			return append(dst, s)
+		}
+		dst = append(dst, s[:i])
+		s = s[i+1:]
+	}
+}
+
+var fieldsPool = sync.Pool{
+	New: func() interface{} {
+		fields := make([]string, 0, 32)
+		return &fields
+	},
+}
+
+func getFields() *[]string { return fieldsPool.Get().(*[]string) }
+
+func putFields(fields *[]string) {
+	// Don't hold references to the sentence.
+	for i := range *fields {
+		(*fields)[i] = ""
+	}
+	*fields = (*fields)[:0]
+	fieldsPool.Put(fields)
+}
+
+// unsafeString returns a string that shares its data with b. The
+// returned string is only valid while b is not modified.
+func unsafeString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return *(*string)(unsafe.Pointer(&b))
+}
+
+// cloneString returns a copy of s that does not share its data.
+func cloneString(s string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return string([]byte(s))
 }
 
 // Register registers the NMEA 0183 type to be parsed into the given
-// destination type, dst. The kind of dst must be a struct, otherwise
-// Register will panic. Calling Register with an already registered
+// destination type, dst. The kind of dst must be a struct and its
+// "nmea" tags must be valid according to Validate, otherwise Register
+// will panic. Calling Register with an already registered
 // type will overwrite the existing registration. If dst is nil, the
 // type will be deregistered.
 //
+// Types may be registered for any talker by replacing the talker ID with
+// "--", for example "--GGA". Registrations for a specific talker take
+// priority over these. Query sentences from any listener to any talker
+// are parsed using the registration for "----Q".
+//
 // The following types are registered by default:
 //
-//  - "AIVDM", "AIVDO": VDMVDO{}
-//  - "GLBOD", "GNBOD", "GPBOD": BOD{}
-//  - "GLBWC", "GNBWC", "GPBWC": BWC{}
-//  - "GLGGA", "GNGGA", "GPGGA": GGA{}
-//  - "GLGLL", "GNGLL", "GPGLL": GLL{}
-//  - "GLGNS", "GNGNS", "GPGNS": GNS{}
-//  - "GLGSA", "GNGSA", "GPGSA": GSA{}
-//  - "GLGSV", "GNGSV", "GPGSV": GSV{}
-//  - "GLHDT", "GNHDT", "GPHDT": HDT{}
-//  - "GLR00", "GNR00", "GPR00": R00{}
-//  - "GLRMA", "GNRMA", "GPRMA": RMA{}
-//  - "GLRMB", "GNRMB", "GPRMB": RMB{}
-//  - "GLRMC", "GNRMC", "GPRMC": RMC{}
-//  - "GLSTN", "GNSTN", "GPSTN": STN{}
-//  - "GLTHS", "GNTHS", "GPTHS": THS{}
-//  - "GLTRF", "GNTRF", "GPTRF": TRF{}
-//  - "GLVBW", "GNVBW", "GPVBW": VBW{}
-//  - "GLVTG", "GNVTG", "GPVTG": VTG{}
-//  - "GLWPL", "GNWPL", "GPWPL": WPL{}
-//  - "GLXTE", "GNXTE", "GPXTE": XTE{}
-//  - "GLZDA", "GNZDA", "GPZDA": ZDA{}
+//  - "--BOD": BOD{}
+//  - "--BWC": BWC{}
+//  - "--GGA": GGA{}
+//  - "--GLL": GLL{}
+//  - "--GNS": GNS{}
+//  - "--GSA": GSA{}
+//  - "--GSV": GSV{}
+//  - "--HDT": HDT{}
+//  - "--R00": R00{}
+//  - "--RMA": RMA{}
+//  - "--RMB": RMB{}
+//  - "--RMC": RMC{}
+//  - "--RTE": RTE{}
+//  - "--STN": STN{}
+//  - "--THS": THS{}
+//  - "--TRF": TRF{}
+//  - "--VBW": VBW{}
+//  - "--VDM", "--VDO": VDMVDO{}
+//  - "--VTG": VTG{}
+//  - "--WPL": WPL{}
+//  - "--XTE": XTE{}
+//  - "--ZDA": ZDA{}
 //  - "PGRME": RME{}
 //  - "PGRMM": RMM{}
 //  - "PGRMZ": RMZ{}
 //  - "PSLIB": LIB{}
+//  - "----Q": Query{}
 //
 func Register(typ string, dst interface{}) {
-	if dst == nil {
-		registryLock.Lock()
-		delete(registry, typ)
-		registryLock.Unlock()
-		return
-	}
-	if reflect.TypeOf(dst).Kind() != reflect.Struct {
-		panic(ErrNotStruct)
-	}
-	registryLock.Lock()
-	registry[typ] = dst
-	registryLock.Unlock()
+	defaultRegistry.Register(typ, dst)
 }
 
-var (
-	registryLock sync.RWMutex
-	registry     = map[string]interface{}{
-		"AIVDM": VDMVDO{},
-		"AIVDO": VDMVDO{},
-		"GLGNS": GNS{}, "GNGNS": GNS{}, "GPGNS": GNS{},
-		"GLBOD": BOD{}, "GNBOD": BOD{}, "GPBOD": BOD{},
-		"GLBWC": BWC{}, "GNBWC": BWC{}, "GPBWC": BWC{},
-		"GLGGA": GGA{}, "GNGGA": GGA{}, "GPGGA": GGA{},
-		"GLGLL": GLL{}, "GNGLL": GLL{}, "GPGLL": GLL{},
-		"GLGSA": GSA{}, "GNGSA": GSA{}, "GPGSA": GSA{},
-		"GLGSV": GSV{}, "GNGSV": GSV{}, "GPGSV": GSV{},
-		"GLHDT": HDT{}, "GNHDT": HDT{}, "GPHDT": HDT{},
-		"GLR00": R00{}, "GNR00": R00{}, "GPR00": R00{},
-		"GLRMA": RMA{}, "GNRMA": RMA{}, "GPRMA": RMA{},
-		"GLRMB": RMB{}, "GNRMB": RMB{}, "GPRMB": RMB{},
-		"GLRMC": RMC{}, "GNRMC": RMC{}, "GPRMC": RMC{},
-		"GLSTN": STN{}, "GNSTN": STN{}, "GPSTN": STN{},
-		"GLTHS": THS{}, "GNTHS": THS{}, "GPTHS": THS{},
-		"GLTRF": TRF{}, "GNTRF": TRF{}, "GPTRF": TRF{},
-		"GLVBW": VBW{}, "GNVBW": VBW{}, "GPVBW": VBW{},
-		"GLVTG": VTG{}, "GNVTG": VTG{}, "GPVTG": VTG{},
-		"GLWPL": WPL{}, "GNWPL": WPL{}, "GPWPL": WPL{},
-		"GLXTE": XTE{}, "GNXTE": XTE{}, "GPXTE": XTE{},
-		"GLZDA": ZDA{}, "GNZDA": ZDA{}, "GPZDA": ZDA{},
-		"PGRME": RME{},
-		"PGRMM": RMM{},
-		"PGRMZ": RMZ{},
-		"PSLIB": LIB{},
-	}
-)
+var defaultRegistry = NewDefaultRegistry()
 
 // Parse parses a raw NMEA 0183 sentence and fills the fields of a destination
 // registered struct with the data contained within the sentence and returns it.
 // If the sentence has a checksum it is compared with the checksum of the
 // sentence's bytes.
 func Parse(sentence string) (interface{}, error) {
-	switch {
-	case len(sentence) < 6: // [!$].{5}
-		return nil, ErrTooShort
-	case sentence[0] != '$' && sentence[0] != '!':
-		return nil, ErrNoSigil
-	}
-	sentence = sentence[1:]
+	return defaultRegistry.Parse(sentence)
+}
 
-	var sum, wantSum int64
-	if sumMarkIdx := strings.Index(sentence, "*"); sumMarkIdx != -1 {
-		var err error
-		wantSum, err = strconv.ParseInt(sentence[sumMarkIdx+1:], 16, 8)
-		if err != nil {
-			return nil, err
-		}
-		sentence = sentence[:sumMarkIdx]
-		sum = checksum(sentence)
-	}
+// ParseBytes is like Parse but parses a sentence held in a byte slice.
+// The sentence is not retained after ParseBytes returns.
+func ParseBytes(sentence []byte) (interface{}, error) {
+	return defaultRegistry.ParseBytes(sentence)
+}
 
-	fields := strings.Split(sentence, ",")
+// ParseWithTagBlock is like Parse but also returns the parameters of any
+// tag blocks preceding the sentence, as described for ParseTagBlock.
+func ParseWithTagBlock(sentence string) (TagBlock, interface{}, error) {
+	return defaultRegistry.ParseWithTagBlock(sentence)
+}
 
-	registryLock.RLock()
-	dst, ok := registry[fields[0]]
-	registryLock.RUnlock()
-	if !ok {
-		return nil, ErrNotRegistered
-	}
+// ParseError is returned when a sentence field cannot be parsed into
+// its destination struct field.
+type ParseError struct {
+	Type   string // Type is the sentence type.
+	Index  int    // Index is the position of the field in the sentence, with the type at 0.
+	Field  string // Field is the name of the destination struct field.
+	Method string // Method is the tag method used to parse the field.
+	Value  string // Value is the raw text of the sentence field.
+	Err    error  // Err is the underlying error.
+}
 
-	typ := reflect.TypeOf(dst)
-	if typ.Kind() != reflect.Struct {
-		return nil, ErrNotStruct
-	}
-	rv := reflect.New(typ).Elem()
-	err := parseTo(rv, fields, wantSum)
-	if sum != wantSum {
-		err = ErrChecksum
+// newParseError returns a ParseError for the field at position pos
+// that is described by f. The Type and Field fields of the returned
+// error are not set.
+func newParseError(f *fieldPlan, pos int, value string, err error) *ParseError {
+	return &ParseError{
+		Index:  pos,
+		Method: f.method,
+		Value:  value,
+		Err:    err,
 	}
-	return rv.Interface(), err
 }
 
-func parseTo(rv reflect.Value, fields []string, sum int64) error {
-	rt := rv.Type()
-
-	var hasType bool
-	for i := 0; i < rv.NumField(); i++ {
-		f := rv.Field(i)
-		tag := rt.Field(i).Tag.Get("nmea")
-		if tag == "" {
-			continue
-		}
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("nmea: %s field %d (%s): cannot parse %q as %s: %v",
+		e.Type, e.Index, e.Field, e.Value, e.Method, e.Err)
+}
 
-		if rt.Field(i).Name == "Type" {
-			if i != 0 {
-				return ErrLateType
-			}
-			if tag[0] == '/' {
-				if tag[len(tag)-1] != '/' {
-					return ErrTypeSyntax
-				}
-				re, err := regexp.Compile(tag[1 : len(tag)-1])
-				if err != nil {
-					return ErrTypeSyntax
-				}
-				if !re.MatchString(fields[i]) {
-					f.SetString(fields[i])
-					return ErrNMEAType
-				}
-			} else if tag != fields[i] {
-				f.SetString(fields[i])
-				return ErrNMEAType
-			}
-			hasType = true
-			if f.Kind() == reflect.String {
-				f.SetString(fields[i])
-			}
-			continue
-		}
+func (e *ParseError) Unwrap() error { return e.Err }
 
-		switch tag {
-		default:
-			if i >= len(fields) {
-				continue
-			}
-			err := methodFor[tag](f, fields[i])
-			if err != nil {
-				return err
-			}
-		case "checksum":
-			switch f.Kind() {
-			default:
-				return ErrType
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				f.SetInt(sum)
-			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-				f.SetUint(uint64(sum))
-			}
-		}
-	}
+// ConstError is the error returned when a sentence field tagged with
+// a const option does not hold the required value.
+type ConstError struct {
+	Want string // Want is the required value.
+	Got  string // Got is the value in the sentence.
+}
 
-	if !hasType {
-		return ErrMissingType
-	}
-	return nil
+func (e *ConstError) Error() string {
+	return fmt.Sprintf("nmea: constant field is %q, want %q", e.Got, e.Want)
 }
 
 func checksum(s string) int64 {
@@ -271,13 +322,75 @@ func checksum(s string) int64 {
 	return int64(sum)
 }
 
-// TODO(kortschak): Add helper method registration.
-var methodFor = map[string]func(dst reflect.Value, field string) error{
-	"number": setNumber,
-	"string": setString,
-	"latlon": setLatLon,
-	"date":   setDate,
-	"time":   setTime,
+// ParseFunc is a field parsing method. It sets dst from the text of
+// a sentence field.
+type ParseFunc func(dst reflect.Value, field string) error
+
+// FormatFunc is a field formatting method. It appends the sentence
+// field text for src to dst and returns the extended buffer.
+type FormatFunc func(dst []byte, src reflect.Value) ([]byte, error)
+
+// RegisterMethod registers the parse and format functions to be used
+// for struct fields with the given method name in their "nmea" tag.
+// The format function is used by Marshal and may be nil if the method
+// does not need to be marshaled. Calling RegisterMethod with an already
+// registered name, including the name of a built-in method, will
+// overwrite the existing registration. If parse is nil, the method will
+// be deregistered.
+//
+// RegisterMethod will panic if name is empty, "checksum" or "text".
+func RegisterMethod(name string, parse ParseFunc, format FormatFunc) {
+	if name == "" || name == "checksum" || name == "text" {
+		panic("nmea: invalid method name: " + strconv.Quote(name))
+	}
+	methodLock.Lock()
+	defer methodLock.Unlock()
+	defer atomic.AddUint64(&methodGen, 1)
+	if parse == nil {
+		delete(methods, name)
+		return
+	}
+	methods[name] = method{parse: parse, format: format}
+}
+
+type method struct {
+	parse  ParseFunc
+	format FormatFunc
+
+	// borrow is an optional parse function that
+	// does not retain the field text. It is used
+	// for parsing sentences held in byte slices.
+	borrow ParseFunc
+
+	// span is the number of sentence fields used by
+	// the method if it is greater than one. Methods
+	// with a span use parseSpan for parsing and their
+	// format function writes all the spanned fields.
+	// parseSpan must not retain the field text.
+	span      int
+	parseSpan func(dst reflect.Value, fields []string) error
+}
+
+var (
+	methodLock sync.RWMutex
+	methods    = map[string]method{
+		"number": {parse: setNumber, format: appendNumber, borrow: setNumber},
+		"string": {parse: setString, format: appendString, borrow: setStringBorrowed},
+		"latlon": {parse: setLatLon, format: appendLatLon, borrow: setLatLon},
+		"date":   {parse: setDate, format: appendDate, borrow: setDate},
+		"time":   {parse: setTime, format: appendTime, borrow: setTime},
+
+		"lat":      {format: appendLat, span: 2, parseSpan: setLat},
+		"lon":      {format: appendLon, span: 2, parseSpan: setLon},
+		"position": {format: appendPosition, span: 4, parseSpan: setPosition},
+	}
+)
+
+func methodFor(name string) (method, bool) {
+	methodLock.RLock()
+	m, ok := methods[name]
+	methodLock.RUnlock()
+	return m, ok
 }
 
 func setNumber(dst reflect.Value, field string) error {
@@ -374,12 +487,17 @@ func setLatLon(dst reflect.Value, field string) error {
 		if err != nil {
 			return err
 		}
-		deg, min := math.Modf(val / 100)
-		dst.SetFloat(deg + min*100.0/60.0)
+		dst.SetFloat(degrees(val))
 	}
 	return nil
 }
 
+// degrees returns the decimal degrees value of an NMEA [d]ddmm.mm value.
+func degrees(val float64) float64 {
+	deg, min := math.Modf(val / 100)
+	return deg + min*100.0/60.0
+}
+
 func setString(dst reflect.Value, field string) error {
 	switch dst.Kind() {
 	default:
@@ -395,8 +513,38 @@ func setString(dst reflect.Value, field string) error {
 	return nil
 }
 
+// setStringBorrowed is the equivalent of setString for fields that
+// may not be retained. It avoids allocation when the destination
+// already holds the field value. Byte slice destinations are
+// overwritten in place, as documented by ParseToBytes.
+func setStringBorrowed(dst reflect.Value, field string) error {
+	switch dst.Kind() {
+	default:
+		return ErrType
+	case reflect.String:
+		if dst.String() != field {
+			dst.SetString(cloneString(field))
+		}
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() != reflect.Uint8 {
+			return ErrType
+		}
+		dst.SetBytes(append(dst.Bytes()[:0], field...))
+	}
+	return nil
+}
+
 var timeType = reflect.TypeOf(time.Time{})
 
+// setTimeValue sets dst to t, avoiding allocation when dst is addressable.
+func setTimeValue(dst reflect.Value, t time.Time) {
+	if dst.CanAddr() {
+		*dst.Addr().Interface().(*time.Time) = t
+		return
+	}
+	dst.Set(reflect.ValueOf(t))
+}
+
 func setDate(dst reflect.Value, field string) error {
 	if dst.Type() != timeType {
 		return ErrType
@@ -405,19 +553,23 @@ func setDate(dst reflect.Value, field string) error {
 	if err != nil {
 		return err
 	}
-	dst.Set(reflect.ValueOf(t))
+	setTimeValue(dst, t)
 	return nil
 }
 
 func setTime(dst reflect.Value, field string) error {
-	if dst.Type() != timeType {
+	if dst.Type() != timeType && dst.Type() != timeOfDayType {
 		return ErrType
 	}
-	t, err := time.ParseInLocation("150405", field, time.UTC)
+	t, err := parseTimeOfDay(field)
 	if err != nil {
 		return err
 	}
-	dst.Set(reflect.ValueOf(t))
+	if dst.Type() == timeOfDayType {
+		dst.SetInt(int64(t))
+		return nil
+	}
+	setTimeValue(dst, time.Date(0, time.January, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(t)))
 	return nil
 }
 
@@ -6,7 +6,9 @@ package nmea
 
 import (
 	"bytes"
+	"errors"
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -141,6 +143,7 @@ var parseTests = []struct {
 			Latitude: 53.01631900000001, NorthSouth: "N",
 			Longitude: 2.9996956666666668, EastWest: "E",
 			Timestamp: time.Date(0, 1, 1, 12, 59, 26, 0, time.UTC),
+			Status:    StatusValid,
 			Checksum:  0x28,
 		},
 	},
@@ -162,6 +165,20 @@ var parseTests = []struct {
 			Latitude: 49.27416666666666, NorthSouth: "N",
 			Longitude: 123.18533333333335, EastWest: "W",
 			Timestamp: time.Date(0, 1, 1, 22, 54, 44, 0, time.UTC),
+			Status:    StatusValid,
+		},
+	},
+	{
+		sentence: "$GPGLL,4916.45,N,12311.12,W,225444,A,D*59",
+		dst:      &GLL{},
+		want: &GLL{
+			Type:     "GPGLL",
+			Latitude: 49.27416666666666, NorthSouth: "N",
+			Longitude: 123.18533333333335, EastWest: "W",
+			Timestamp: time.Date(0, 1, 1, 22, 54, 44, 0, time.UTC),
+			Status:    StatusValid,
+			Mode:      FAAModeDifferential,
+			Checksum:  0x59,
 		},
 	},
 	{
@@ -171,10 +188,7 @@ var parseTests = []struct {
 			Type:     "GPGSA",
 			Mode:     "A",
 			Fix:      3,
-			SV5:      "16",
-			SV6:      "18",
-			SV8:      "22",
-			SV9:      "24",
+			SVs:      [12]string{"", "", "", "", "", "16", "18", "", "22", "24"},
 			PDOP:     3.6,
 			HDOP:     2.1,
 			VDOP:     2.2,
@@ -188,14 +202,7 @@ var parseTests = []struct {
 			Type:     "GPGSA",
 			Mode:     "A",
 			Fix:      3,
-			SV0:      "19",
-			SV1:      "28",
-			SV2:      "14",
-			SV3:      "18",
-			SV4:      "27",
-			SV5:      "22",
-			SV6:      "31",
-			SV7:      "39",
+			SVs:      [12]string{"19", "28", "14", "18", "27", "22", "31", "39"},
 			PDOP:     1.7,
 			HDOP:     1,
 			VDOP:     1.3,
@@ -210,23 +217,13 @@ var parseTests = []struct {
 			Messages:         3,
 			MessageNumber:    1,
 			SatellitesInView: 11,
-			Satellite0PRN:    3,
-			Elevation0:       3,
-			Azimuth0:         111,
-			SNR0:             0,
-			Satellite1PRN:    4,
-			Elevation1:       15,
-			Azimuth1:         270,
-			SNR1:             0,
-			Satellite2PRN:    6,
-			Elevation2:       1,
-			Azimuth2:         10,
-			SNR2:             0,
-			Satellite3PRN:    13,
-			Elevation3:       6,
-			Azimuth3:         292,
-			SNR3:             0,
-			Checksum:         0x74,
+			Satellites: []GSVSatellite{
+				{PRN: 3, Elevation: 3, Azimuth: 111, SNR: 0},
+				{PRN: 4, Elevation: 15, Azimuth: 270, SNR: 0},
+				{PRN: 6, Elevation: 1, Azimuth: 10, SNR: 0},
+				{PRN: 13, Elevation: 6, Azimuth: 292, SNR: 0},
+			},
+			Checksum: 0x74,
 		},
 	},
 	{
@@ -237,23 +234,13 @@ var parseTests = []struct {
 			Messages:         3,
 			MessageNumber:    2,
 			SatellitesInView: 11,
-			Satellite0PRN:    14,
-			Elevation0:       25,
-			Azimuth0:         170,
-			SNR0:             0,
-			Satellite1PRN:    16,
-			Elevation1:       57,
-			Azimuth1:         208,
-			SNR1:             39,
-			Satellite2PRN:    18,
-			Elevation2:       67,
-			Azimuth2:         296,
-			SNR2:             40,
-			Satellite3PRN:    19,
-			Elevation3:       40,
-			Azimuth3:         246,
-			SNR3:             0,
-			Checksum:         0x74,
+			Satellites: []GSVSatellite{
+				{PRN: 14, Elevation: 25, Azimuth: 170, SNR: 0},
+				{PRN: 16, Elevation: 57, Azimuth: 208, SNR: 39},
+				{PRN: 18, Elevation: 67, Azimuth: 296, SNR: 40},
+				{PRN: 19, Elevation: 40, Azimuth: 246, SNR: 0},
+			},
+			Checksum: 0x74,
 		},
 	},
 	{
@@ -264,19 +251,12 @@ var parseTests = []struct {
 			Messages:         3,
 			MessageNumber:    3,
 			SatellitesInView: 11,
-			Satellite0PRN:    22,
-			Elevation0:       42,
-			Azimuth0:         67,
-			SNR0:             42,
-			Satellite1PRN:    24,
-			Elevation1:       14,
-			Azimuth1:         311,
-			SNR1:             43,
-			Satellite2PRN:    27,
-			Elevation2:       5,
-			Azimuth2:         244,
-			SNR2:             0,
-			Checksum:         0x4d,
+			Satellites: []GSVSatellite{
+				{PRN: 22, Elevation: 42, Azimuth: 67, SNR: 42},
+				{PRN: 24, Elevation: 14, Azimuth: 311, SNR: 43},
+				{PRN: 27, Elevation: 5, Azimuth: 244, SNR: 0},
+			},
+			Checksum: 0x4d,
 		},
 	},
 	{
@@ -287,23 +267,13 @@ var parseTests = []struct {
 			Messages:         1,
 			MessageNumber:    1,
 			SatellitesInView: 13,
-			Satellite0PRN:    2,
-			Elevation0:       2,
-			Azimuth0:         213,
-			SNR0:             0,
-			Satellite1PRN:    3,
-			Elevation1:       -3,
-			Azimuth1:         0,
-			SNR1:             0,
-			Satellite2PRN:    11,
-			Elevation2:       0,
-			Azimuth2:         121,
-			SNR2:             0,
-			Satellite3PRN:    14,
-			Elevation3:       13,
-			Azimuth3:         172,
-			SNR3:             5,
-			Checksum:         0x62,
+			Satellites: []GSVSatellite{
+				{PRN: 2, Elevation: 2, Azimuth: 213, SNR: 0},
+				{PRN: 3, Elevation: -3, Azimuth: 0, SNR: 0},
+				{PRN: 11, Elevation: 0, Azimuth: 121, SNR: 0},
+				{PRN: 14, Elevation: 13, Azimuth: 172, SNR: 5},
+			},
+			Checksum: 0x62,
 		},
 	},
 	{
@@ -319,34 +289,18 @@ var parseTests = []struct {
 		sentence: "$GPR00,EGLL,EGLM,EGTB,EGUB,EGTK,MBOT,EGTB,,,,,,,*58",
 		dst:      &R00{},
 		want: &R00{
-			Type:     "GPR00",
-			WP0:      "EGLL",
-			WP1:      "EGLM",
-			WP2:      "EGTB",
-			WP3:      "EGUB",
-			WP4:      "EGTK",
-			WP5:      "MBOT",
-			WP6:      "EGTB",
-			Checksum: 0x58,
+			Type:      "GPR00",
+			Waypoints: []string{"EGLL", "EGLM", "EGTB", "EGUB", "EGTK", "MBOT", "EGTB"},
+			Checksum:  0x58,
 		},
 	},
 	{
 		sentence: "$GPR00,MINST,CHATN,CHAT1,CHATW,CHATM,CHATE,003,004,005,006,007,,,*05",
 		dst:      &R00{},
 		want: &R00{
-			Type:     "GPR00",
-			WP0:      "MINST",
-			WP1:      "CHATN",
-			WP2:      "CHAT1",
-			WP3:      "CHATW",
-			WP4:      "CHATM",
-			WP5:      "CHATE",
-			WP6:      "003",
-			WP7:      "004",
-			WP8:      "005",
-			WP9:      "006",
-			WP10:     "007",
-			Checksum: 0x5,
+			Type:      "GPR00",
+			Waypoints: []string{"MINST", "CHATN", "CHAT1", "CHATW", "CHATM", "CHATE", "003", "004", "005", "006", "007"},
+			Checksum:  0x5,
 		},
 	},
 	{
@@ -449,6 +403,32 @@ var parseTests = []struct {
 			Checksum: 0x70,
 		},
 	},
+	{
+		sentence: "$GPRTE,2,1,c,0,PBRCPK,PBRTO,PTELGR,PPLAND,PYAMBU,PPFAIR,PWARRN,PMORTL,PLISMR*73",
+		dst:      &RTE{},
+		want: &RTE{
+			Type:          "GPRTE",
+			Messages:      2,
+			MessageNumber: 1,
+			Mode:          "c",
+			Route:         "0",
+			Waypoints:     []string{"PBRCPK", "PBRTO", "PTELGR", "PPLAND", "PYAMBU", "PPFAIR", "PWARRN", "PMORTL", "PLISMR"},
+			Checksum:      0x73,
+		},
+	},
+	{
+		sentence: "$GPRTE,2,2,c,0,PCRESY,GRYRIE,GCORIO,GWERR,GWESTG,7FED*34",
+		dst:      &RTE{},
+		want: &RTE{
+			Type:          "GPRTE",
+			Messages:      2,
+			MessageNumber: 2,
+			Mode:          "c",
+			Route:         "0",
+			Waypoints:     []string{"PCRESY", "GRYRIE", "GCORIO", "GWERR", "GWESTG", "7FED"},
+			Checksum:      0x34,
+		},
+	},
 	{
 		sentence: "$GPTRF,053220.03,051197,4916.45,N,12311.12,W,1.2,3.4,5.6,7.8,SAT",
 		dst:      &TRF{},
@@ -782,6 +762,105 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseToBytes(t *testing.T) {
+	for _, test := range parseTests {
+		dst := reflect.New(reflect.TypeOf(test.dst).Elem()).Interface()
+		buf := []byte(test.sentence)
+		err := ParseToBytes(dst, buf)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		// Clobber the buffer to check that it is not retained.
+		for i := range buf {
+			buf[i] = 'x'
+		}
+		if !reflect.DeepEqual(dst, test.want) {
+			t.Errorf("unexpected result:\ngot: %#v\nwant:%#v", dst, test.want)
+		}
+	}
+}
+
+func TestParseToBytesReuse(t *testing.T) {
+	var dst struct {
+		Type string `nmea:"PXRAW"`
+		Data []byte `nmea:"string"`
+	}
+	err := ParseToBytes(&dst, []byte("$PXRAW,first"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := dst.Data
+
+	// A retained slice is overwritten by the next parse.
+	err = ParseToBytes(&dst, []byte("$PXRAW,other"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(first) != "other" {
+		t.Errorf("unexpected retained slice value: got:%q want:%q", first, "other")
+	}
+
+	// Setting the field to nil prevents reuse.
+	first = dst.Data
+	dst.Data = nil
+	err = ParseToBytes(&dst, []byte("$PXRAW,third"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(first) != "other" || string(dst.Data) != "third" {
+		t.Errorf("unexpected values: got:%q %q want:%q %q", first, dst.Data, "other", "third")
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	for _, test := range parseTests {
+		buf := []byte(test.sentence)
+		got, err := ParseBytes(buf)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		for i := range buf {
+			buf[i] = 'x'
+		}
+		want := reflect.ValueOf(test.want).Elem().Interface()
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected result:\ngot: %#v\nwant:%#v", got, want)
+		}
+	}
+
+	buf := []byte("$GPGGA,123519,4807.038,N,01131.000,W,1,2,3,x,M,5,M,,")
+	_, err := ParseBytes(buf)
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *ParseError: got:%T", err)
+	}
+	for i := range buf {
+		buf[i] = 'x'
+	}
+	if perr.Type != "GPGGA" || perr.Value != "x" {
+		t.Errorf("error retained sentence buffer: %v", perr)
+	}
+}
+
+func TestParseToBytesAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not reliable with the race detector")
+	}
+	for _, test := range parseTests {
+		dst := reflect.New(reflect.TypeOf(test.dst).Elem()).Interface()
+		buf := []byte(test.sentence)
+		allocs := testing.AllocsPerRun(10, func() {
+			err := ParseToBytes(dst, buf)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("unexpected allocations for %q: %v", test.sentence, allocs)
+		}
+	}
+}
+
 var aisArmorTests = []struct {
 	payload  string
 	padding  int
@@ -857,3 +936,580 @@ func TestDeArmorAIS(t *testing.T) {
 		}
 	}
 }
+
+type hexTest struct {
+	Type string `nmea:"PXHEX"`
+
+	Value uint16 `nmea:"hex"`
+	Flag  bool   `nmea:"bool"`
+
+	Checksum byte `nmea:"checksum"`
+}
+
+func TestRegisterMethod(t *testing.T) {
+	const sentence = "$PXHEX,fe12,A*1C"
+
+	err := ParseTo(&hexTest{}, sentence)
+	if !errors.Is(err, ErrUnknownMethod) {
+		t.Errorf("unexpected error for unregistered method: got:%v want:%v", err, ErrUnknownMethod)
+	}
+
+	RegisterMethod("hex",
+		func(dst reflect.Value, field string) error {
+			v, err := strconv.ParseUint(field, 16, 16)
+			if err != nil {
+				return err
+			}
+			dst.SetUint(v)
+			return nil
+		},
+		func(dst []byte, src reflect.Value) ([]byte, error) {
+			return strconv.AppendUint(dst, src.Uint(), 16), nil
+		},
+	)
+	RegisterMethod("bool",
+		func(dst reflect.Value, field string) error {
+			dst.SetBool(field == "A")
+			return nil
+		},
+		func(dst []byte, src reflect.Value) ([]byte, error) {
+			if src.Bool() {
+				return append(dst, 'A'), nil
+			}
+			return append(dst, 'V'), nil
+		},
+	)
+	defer func() {
+		RegisterMethod("hex", nil, nil)
+		RegisterMethod("bool", nil, nil)
+	}()
+
+	var got hexTest
+	err = ParseTo(&got, sentence)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	want := hexTest{Type: "PXHEX", Value: 0xfe12, Flag: true, Checksum: 0x1c}
+	if got != want {
+		t.Errorf("unexpected result:\ngot: %#v\nwant:%#v", got, want)
+	}
+	s, err := Marshal(got)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if s != sentence {
+		t.Errorf("unexpected marshal result: got:%q want:%q", s, sentence)
+	}
+}
+
+type badTags struct {
+	Name     string  `nmea:"string"`
+	Type     string  `nmea:"/G[LNP/"`
+	Value    float64 `nmea:"numbr"`
+	Position string  `nmea:"latlon"`
+	Checksum string  `nmea:"checksum"`
+}
+
+func TestValidate(t *testing.T) {
+	for _, test := range parseTests {
+		err := Validate(test.dst)
+		if err != nil {
+			t.Errorf("unexpected error for %T: %v", test.dst, err)
+		}
+	}
+
+	err := Validate(badTags{})
+	var got []error
+	if errs, ok := err.(TagErrors); ok {
+		for _, e := range errs {
+			got = append(got, e.Err)
+		}
+	} else {
+		t.Fatalf("unexpected error type: %T", err)
+	}
+	want := []error{ErrLateType, ErrTypeSyntax, ErrUnknownMethod, ErrType, ErrType}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected errors:\ngot: %v\nwant:%v", got, want)
+	}
+
+	err = ParseTo(&badTags{}, "$GPGGA,1,2,3,4")
+	if !errors.Is(err, ErrUnknownMethod) {
+		t.Errorf("expected unknown method error from ParseTo: got:%v", err)
+	}
+
+	err = Validate(struct{ A int }{})
+	if !errors.Is(err, ErrMissingType) {
+		t.Errorf("expected missing type error: got:%v", err)
+	}
+	// Check the Is and As methods directly since errors.Is
+	// and errors.As also use the Unwrap list in Go 1.20+.
+	errs := err.(TagErrors)
+	if !errs.Is(ErrMissingType) || errs.Is(ErrUnknownMethod) {
+		t.Errorf("unexpected result from TagErrors.Is for %v", err)
+	}
+	var terr *TagError
+	if !errs.As(&terr) || terr.Err != ErrMissingType {
+		t.Errorf("unexpected result from TagErrors.As for %v: got:%v", err, terr)
+	}
+	func() {
+		defer func() {
+			r := recover()
+			if _, ok := r.(TagErrors); !ok {
+				t.Errorf("expected TagErrors panic from Register: got:%v", r)
+			}
+		}()
+		Register("GPBAD", badTags{})
+	}()
+}
+
+func TestParseError(t *testing.T) {
+	_, err := Parse("$GPGGA,123519,4807.038,N,01131.000,W,1,2,3,x,M,5,M,,")
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *ParseError: got:%T", err)
+	}
+	want := &ParseError{Type: "GPGGA", Index: 9, Field: "Altitude", Method: "number", Value: "x", Err: perr.Err}
+	if !reflect.DeepEqual(perr, want) {
+		t.Errorf("unexpected error:\ngot: %#v\nwant:%#v", perr, want)
+	}
+	if _, ok := perr.Err.(*strconv.NumError); !ok {
+		t.Errorf("unexpected wrapped error type: %T", perr.Err)
+	}
+}
+
+func TestEmptyTime(t *testing.T) {
+	const sentence = "$GPRMC,,V,,,,,,,,,N"
+	var rmc RMC
+	err := ParseTo(&rmc, sentence)
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *ParseError for empty time: got:%v", err)
+	}
+	if perr.Field != "Time" || perr.Err != ErrTime {
+		t.Errorf("unexpected error for empty time: got:%v", err)
+	}
+
+	var opt struct {
+		Type string     `nmea:"GPRMC"`
+		Time *time.Time `nmea:"time"`
+		Date *time.Time `nmea:"date,@9"`
+	}
+	err = ParseTo(&opt, sentence)
+	if err != nil {
+		t.Errorf("unexpected error for empty optional time: %v", err)
+	}
+	if opt.Time != nil || opt.Date != nil {
+		t.Errorf("unexpected values for empty optional time: got:%v %v", opt.Time, opt.Date)
+	}
+}
+
+func BenchmarkParseTo(b *testing.B) {
+	dsts := make([]interface{}, len(parseTests))
+	for i, test := range parseTests {
+		dsts[i] = reflect.New(reflect.TypeOf(test.dst).Elem()).Interface()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, test := range parseTests {
+			err := ParseTo(dsts[j], test.sentence)
+			if err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+}
+
+func BenchmarkParseToBytes(b *testing.B) {
+	dsts := make([]interface{}, len(parseTests))
+	sentences := make([][]byte, len(parseTests))
+	for i, test := range parseTests {
+		dsts[i] = reflect.New(reflect.TypeOf(test.dst).Elem()).Interface()
+		sentences[i] = []byte(test.sentence)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range sentences {
+			err := ParseToBytes(dsts[j], sentences[j])
+			if err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, test := range parseTests {
+			_, err := Parse(test.sentence)
+			if err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+}
+
+type optionalGGA struct {
+	Type string `nmea:"/G[LNP]GGA/"`
+
+	Timestamp  *time.Time `nmea:"time"`
+	Latitude   *float64   `nmea:"latlon"`
+	NorthSouth *string    `nmea:"string"`
+	Longitude  *float64   `nmea:"latlon"`
+	EastWest   *string    `nmea:"string"`
+
+	Quality    *int `nmea:"number"`
+	Satellites *int `nmea:"number"`
+
+	HDOP *float64 `nmea:"number"`
+
+	Altitude     *float64 `nmea:"number"`
+	AltitudeUnit *string  `nmea:"string"`
+
+	Separation     *float64 `nmea:"number"`
+	SeparationUnit *string  `nmea:"string"`
+
+	Age *float64 `nmea:"number"`
+}
+
+func TestOptionalFields(t *testing.T) {
+	const sentence = "$GPGGA,123519,,,,,1,0,,0,M,,,*0B"
+	for _, borrowed := range []bool{false, true} {
+		var got optionalGGA
+		var err error
+		if borrowed {
+			err = ParseToBytes(&got, []byte(sentence))
+		} else {
+			err = ParseTo(&got, sentence)
+		}
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if got.Timestamp == nil || *got.Timestamp != time.Date(0, 1, 1, 12, 35, 19, 0, time.UTC) {
+			t.Errorf("unexpected timestamp: %v", got.Timestamp)
+		}
+		if got.Latitude != nil || got.NorthSouth != nil || got.HDOP != nil || got.Separation != nil || got.Age != nil {
+			t.Errorf("unexpected non-nil field for empty value: %+v", got)
+		}
+		if got.Satellites == nil || *got.Satellites != 0 {
+			t.Errorf("unexpected satellites: %v", got.Satellites)
+		}
+		if got.Altitude == nil || *got.Altitude != 0 {
+			t.Errorf("unexpected altitude: %v", got.Altitude)
+		}
+		if got.AltitudeUnit == nil || *got.AltitudeUnit != "M" {
+			t.Errorf("unexpected altitude unit: %v", got.AltitudeUnit)
+		}
+
+		s, err := Marshal(got)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if s != sentence {
+			t.Errorf("unexpected marshal result: got:%q want:%q", s, sentence)
+		}
+	}
+
+	// Existing values are cleared by empty fields.
+	got := optionalGGA{HDOP: new(float64)}
+	err := ParseTo(&got, sentence)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got.HDOP != nil {
+		t.Errorf("expected nil HDOP: got:%v", *got.HDOP)
+	}
+}
+
+type badRepeat struct {
+	Type string `nmea:"PXBAD"`
+
+	Slice  []int   `nmea:"number"`
+	Array  [3]int  `nmea:"number,repeat=2"`
+	Scalar int     `nmea:"number,repeat=2"`
+	Option float64 `nmea:"number,scale=2"`
+}
+
+func TestRepeatedFields(t *testing.T) {
+	err := Validate(badRepeat{})
+	var got []error
+	if errs, ok := err.(TagErrors); ok {
+		for _, e := range errs {
+			got = append(got, e.Err)
+		}
+	} else {
+		t.Fatalf("unexpected error type: %T", err)
+	}
+	want := []error{ErrRepeat, ErrRepeat, ErrRepeat, ErrTagSyntax}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected errors:\ngot: %v\nwant:%v", got, want)
+	}
+
+	// Reused slices are resized for each sentence.
+	var gsv GSV
+	for _, test := range []struct {
+		sentence string
+		want     int
+	}{
+		{sentence: "$GPGSV,3,1,11,03,03,111,00,04,15,270,00,06,01,010,00,13,06,292,00*74", want: 4},
+		{sentence: "$GPGSV,3,3,11,22,42,067,42,24,14,311,43,27,05,244,00,,,,*4D", want: 3},
+		{sentence: "$GPGSV,3,3,11,22,42,067,42", want: 1},
+	} {
+		err := ParseTo(&gsv, test.sentence)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(gsv.Satellites) != test.want {
+			t.Errorf("unexpected number of satellites for %q: got:%d want:%d", test.sentence, len(gsv.Satellites), test.want)
+		}
+	}
+
+	_, err = Parse("$GPGSV,3,3,11,22,42,067,42,24,14,x,43")
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *ParseError: got:%T", err)
+	}
+	if perr.Field != "Satellites[1].Azimuth" || perr.Index != 10 {
+		t.Errorf("unexpected error location: field:%s index:%d", perr.Field, perr.Index)
+	}
+
+	_, err = Marshal(R00{Type: "GPR00", Waypoints: make([]string, 15)})
+	if err != ErrRepeat {
+		t.Errorf("unexpected error for long slice: got:%v want:%v", err, ErrRepeat)
+	}
+	s, err := Marshal(GSV{Type: "GPGSV", Messages: 1, MessageNumber: 1, SatellitesInView: 1,
+		Satellites: []GSVSatellite{{PRN: 22, Elevation: 42, Azimuth: 67, SNR: 42}},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	const wantGSV = "$GPGSV,1,1,1,22,42,67,42,,,,,,,,,,,,*49"
+	if s != wantGSV {
+		t.Errorf("unexpected marshal result: got:%q want:%q", s, wantGSV)
+	}
+}
+
+type restTest struct {
+	Type string `nmea:"PXRST"`
+
+	Name   string    `nmea:"string"`
+	Values []float64 `nmea:"number,rest"`
+}
+
+type badRest struct {
+	Type string `nmea:"PXBAD"`
+
+	Values []float64 `nmea:"number,rest"`
+	After  string    `nmea:"string"`
+	Array  [2]int    `nmea:"number,rest"`
+}
+
+func TestRestFields(t *testing.T) {
+	var got restTest
+	for _, test := range []struct {
+		sentence string
+		want     []float64
+	}{
+		{sentence: "$PXRST,a,1,2.5,,4", want: []float64{1, 2.5, 0, 4}},
+		{sentence: "$PXRST,a,1", want: []float64{1}},
+		{sentence: "$PXRST,a,", want: []float64{0}},
+	} {
+		err := ParseTo(&got, test.sentence)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got.Values, test.want) {
+			t.Errorf("unexpected values for %q: got:%v want:%v", test.sentence, got.Values, test.want)
+		}
+		s, err := Marshal(got)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		var rt restTest
+		err = ParseTo(&rt, s)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(rt.Values, test.want) {
+			t.Errorf("unexpected round trip values for %q via %q: got:%v want:%v", test.sentence, s, rt.Values, test.want)
+		}
+	}
+
+	err := Validate(badRest{})
+	var errs []error
+	if e, ok := err.(TagErrors); ok {
+		for _, e := range e {
+			errs = append(errs, e.Err)
+		}
+	} else {
+		t.Fatalf("unexpected error type: %T", err)
+	}
+	want := []error{ErrLateRest, ErrLateRest, ErrTagSyntax}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("unexpected errors:\ngot: %v\nwant:%v", errs, want)
+	}
+}
+
+type posTest struct {
+	Type string `nmea:"PXPOS"`
+
+	Speed  float64 `nmea:"number,@4"`
+	Unit   string  `nmea:"string"`
+	Course float64 `nmea:"number,@1"`
+	Name   string  `nmea:"string,@7"`
+
+	helper int
+}
+
+type badPos struct {
+	Type string `nmea:"PXBAD"`
+
+	Type0   int   `nmea:"number,@0"`
+	A       int   `nmea:"number,@2"`
+	Overlap int   `nmea:"number,@2"`
+	Rest    []int `nmea:"number,rest,@4"`
+	Late    int   `nmea:"number,@5"`
+	Twice   int   `nmea:"number,@6,@7"`
+}
+
+func TestFieldPositions(t *testing.T) {
+	const sentence = "$PXPOS,12.5,,,3.25,N,,Name*03"
+	var got posTest
+	err := ParseTo(&got, sentence)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := posTest{Type: "PXPOS", Speed: 3.25, Unit: "N", Course: 12.5, Name: "Name"}
+	if got != want {
+		t.Errorf("unexpected result:\ngot: %#v\nwant:%#v", got, want)
+	}
+	s, err := Marshal(got)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if s != sentence {
+		t.Errorf("unexpected marshal result: got:%q want:%q", s, sentence)
+	}
+
+	err = Validate(badPos{})
+	var errs []error
+	if e, ok := err.(TagErrors); ok {
+		for _, e := range e {
+			errs = append(errs, e.Err)
+		}
+	} else {
+		t.Fatalf("unexpected error type: %T", err)
+	}
+	wantErrs := []error{ErrTagSyntax, ErrPosition, ErrPosition, ErrLateRest, ErrLateRest}
+	if !reflect.DeepEqual(errs, wantErrs) {
+		t.Errorf("unexpected errors:\ngot: %v\nwant:%v", errs, wantErrs)
+	}
+}
+
+type badConst struct {
+	Type string `nmea:"PXBAD"`
+
+	Method int      `nmea:"number,const=T"`
+	Empty  [0]byte  `nmea:"const="`
+	Repeat []string `nmea:"const=T,repeat=2"`
+}
+
+func TestConstFields(t *testing.T) {
+	const shifted = "$GPVTG,360.0,348.7,M,000.0,N,000.0,K"
+	for _, parse := range []struct {
+		name string
+		fn   func(string) (interface{}, error)
+	}{
+		{name: "Parse", fn: Parse},
+		{name: "ParseBytes", fn: func(s string) (interface{}, error) { return ParseBytes([]byte(s)) }},
+	} {
+		_, err := parse.fn(shifted)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("%s: expected *ParseError: got:%T", parse.name, err)
+		}
+		if perr.Index != 2 || perr.Method != "const" {
+			t.Errorf("%s: unexpected error: %v", parse.name, perr)
+		}
+		var cerr *ConstError
+		if !errors.As(err, &cerr) {
+			t.Fatalf("%s: expected *ConstError: got:%T", parse.name, perr.Err)
+		}
+		want := &ConstError{Want: "T", Got: "348.7"}
+		if !reflect.DeepEqual(cerr, want) {
+			t.Errorf("%s: unexpected error:\ngot: %#v\nwant:%#v", parse.name, cerr, want)
+		}
+	}
+
+	err := Validate(badConst{})
+	var errs []error
+	if e, ok := err.(TagErrors); ok {
+		for _, e := range e {
+			errs = append(errs, e.Err)
+		}
+	} else {
+		t.Fatalf("unexpected error type: %T", err)
+	}
+	wantErrs := []error{ErrTagSyntax, ErrTagSyntax, ErrTagSyntax}
+	if !reflect.DeepEqual(errs, wantErrs) {
+		t.Errorf("unexpected errors:\ngot: %v\nwant:%v", errs, wantErrs)
+	}
+}
+
+type nestedTest struct {
+	Type string `nmea:"PXNST"`
+
+	LatLon
+	Fix fixBlock
+
+	Checksum byte `nmea:"checksum"`
+}
+
+type fixBlock struct {
+	Quality  int     `nmea:"number"`
+	Altitude float64 `nmea:"number"`
+	_        [0]byte `nmea:"const=M"`
+}
+
+func TestNestedStructs(t *testing.T) {
+	const sentence = "$PXNST,4807.038,N,01131.000,W,1,545.4,M*14"
+	var got nestedTest
+	err := ParseTo(&got, sentence)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := nestedTest{
+		Type:     "PXNST",
+		LatLon:   LatLon{Latitude: 48.117299999999986, NorthSouth: "N", Longitude: 11.516666666666667, EastWest: "W"},
+		Fix:      fixBlock{Quality: 1, Altitude: 545.4},
+		Checksum: 0x14,
+	}
+	if got != want {
+		t.Errorf("unexpected result:\ngot: %#v\nwant:%#v", got, want)
+	}
+	if pos := got.Position(); pos != (Position{Lat: 48.117299999999986, Lon: -11.516666666666667}) {
+		t.Errorf("unexpected position: %+v", pos)
+	}
+
+	s, err := Marshal(got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rt nestedTest
+	err = ParseTo(&rt, s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rt.Checksum = got.Checksum
+	if rt != got {
+		t.Errorf("unexpected round trip result via %q:\ngot: %#v\nwant:%#v", s, rt, got)
+	}
+
+	err = ParseTo(&got, "$PXNST,4807.038,N,01131.000,W,1,x,M")
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *ParseError: got:%T", err)
+	}
+	if perr.Field != "Fix.Altitude" || perr.Index != 6 {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,829 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// plan is the compiled parsing and formatting plan for a
+// destination struct type.
+type plan struct {
+	// gen is the method registration generation
+	// that the plan was compiled against.
+	gen uint64
+
+	// literal is the literal sentence type and re is
+	// the sentence type pattern. Only one is used.
+	literal string
+	re      *regexp.Regexp
+
+	// storeType is whether the Type field is a string.
+	storeType bool
+
+	// fields holds the tagged fields of the struct
+	// in sentence order.
+	fields []fieldPlan
+	// sums holds the indexes of checksum fields.
+	sums []int
+	// width is the number of sentence fields described
+	// by the struct, including the sentence type.
+	width int
+
+	// err is the result of validating the struct's tags.
+	err error
+}
+
+// fieldKind is the kind of a fieldPlan.
+type fieldKind int
+
+const (
+	scalarField fieldKind = iota // A field filled by a method.
+	arrayField                   // An array of repeated elements.
+	sliceField                   // A slice of repeated elements.
+	structField                  // A struct holding tagged fields.
+)
+
+// fieldPlan is the compiled plan for a single struct field or,
+// for repeated fields, an element of the field.
+type fieldPlan struct {
+	kind fieldKind
+
+	index  int    // index is the struct field index.
+	pos    int    // pos is the sentence field position relative to the parent.
+	width  int    // width is the number of sentence fields used.
+	name   string // name is the struct field name.
+	method string // method is the tag method name.
+
+	// Method functions for scalarField.
+	parse     ParseFunc
+	format    FormatFunc
+	borrow    ParseFunc
+	parseSpan func(dst reflect.Value, fields []string) error
+
+	// repeat and elem describe the elements
+	// of arrayField and sliceField. If rest is
+	// true, the slice holds all the remaining
+	// sentence fields and repeat is zero.
+	repeat int
+	rest   bool
+	elem   *fieldPlan
+
+	// fields holds the fields of a structField.
+	fields []fieldPlan
+
+	// hemi is one more than the index in the parent's
+	// fields of a hemisphere field that follows a
+	// "latlon" field, or zero. If the hemisphere is
+	// "E" or "W", the field is formatted by lonFormat.
+	hemi      int
+	lonFormat FormatFunc
+}
+
+// tagSpec is a parsed "nmea" field tag.
+type tagSpec struct {
+	method string
+	repeat int
+	rest   bool
+
+	// constant is the required value
+	// of a constant field.
+	constant string
+
+	// pos is the explicit sentence field
+	// position given if at is true.
+	pos int
+	at  bool
+}
+
+// parseTag parses a field tag of the form "method[,option...]". Options
+// take the form name=value, are the flag "rest" or are an explicit field
+// position, @n. The method may be omitted.
+func parseTag(tag string) (tagSpec, error) {
+	var spec tagSpec
+	for i, opt := range strings.Split(tag, ",") {
+		if opt == "rest" {
+			spec.rest = true
+			continue
+		}
+		if strings.HasPrefix(opt, "@") {
+			n, err := strconv.Atoi(opt[1:])
+			if err != nil || n < 0 || spec.at {
+				return spec, ErrTagSyntax
+			}
+			spec.pos = n
+			spec.at = true
+			continue
+		}
+		eq := strings.IndexByte(opt, '=')
+		if eq < 0 {
+			if i != 0 {
+				return spec, ErrTagSyntax
+			}
+			spec.method = opt
+			continue
+		}
+		switch name, val := opt[:eq], opt[eq+1:]; name {
+		case "repeat":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return spec, ErrRepeat
+			}
+			spec.repeat = n
+		case "const":
+			if val == "" || strings.ContainsAny(val, reserved) {
+				return spec, ErrTagSyntax
+			}
+			spec.constant = val
+		default:
+			return spec, ErrTagSyntax
+		}
+	}
+	return spec, nil
+}
+
+var (
+	// plans is the cache of compiled plans keyed by reflect.Type.
+	plans sync.Map
+
+	// methodGen is incremented when the method registry changes,
+	// invalidating all cached plans.
+	methodGen uint64
+)
+
+// planFor returns the plan for the struct type rt, compiling it if
+// no valid plan is cached.
+func planFor(rt reflect.Type) *plan {
+	gen := atomic.LoadUint64(&methodGen)
+	if p, ok := plans.Load(rt); ok && p.(*plan).gen == gen {
+		return p.(*plan)
+	}
+	p := compile(rt, gen)
+	plans.Store(rt, p)
+	return p
+}
+
+// compile returns the plan for the struct type rt. Any problems with
+// the struct's tags are recorded as a TagErrors in the err field of the
+// returned plan.
+func compile(rt reflect.Type, gen uint64) *plan {
+	p := &plan{gen: gen}
+	c := compiler{top: rt}
+
+	var (
+		hasType bool
+		rest    string // rest is the name of any rest field.
+
+		// next is the position of the next
+		// sequentially placed field.
+		next int
+	)
+	explicit := hasPositions(rt)
+	var cols []column
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag := f.Tag.Get("nmea")
+		if tag == "" && !implicit(f.Type) {
+			if !explicit {
+				next++
+			}
+			continue
+		}
+
+		if f.Name == "Type" && tag != "" {
+			next++
+			hasType = true
+			if i != 0 {
+				c.report(f.Name, tag, ErrLateType)
+			}
+			p.storeType = f.Type.Kind() == reflect.String
+			if tag[0] != '/' {
+				p.literal = tag
+				continue
+			}
+			if len(tag) < 2 || tag[len(tag)-1] != '/' {
+				c.report(f.Name, tag, ErrTypeSyntax)
+				continue
+			}
+			re, err := regexp.Compile(tag[1 : len(tag)-1])
+			if err != nil {
+				c.report(f.Name, tag, ErrTypeSyntax)
+				continue
+			}
+			p.re = re
+			continue
+		}
+
+		if tag == "checksum" {
+			switch f.Type.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				p.sums = append(p.sums, i)
+			default:
+				c.report(f.Name, tag, ErrType)
+			}
+			continue
+		}
+
+		if rest != "" && !explicit {
+			c.report(f.Name, tag, ErrLateRest)
+		}
+		fp, ok := c.field(f.Name, f.Type, tag, &next)
+		if ok {
+			fp.index = i
+			p.fields = append(p.fields, fp)
+		}
+		if fp.rest {
+			rest = f.Name
+		}
+		cols = append(cols, column{name: f.Name, tag: tag, pos: fp.pos, width: fp.width, rest: fp.rest})
+	}
+	p.width = next
+	if explicit {
+		p.width = c.layout(cols, 1, p.width)
+	}
+	if !hasType {
+		c.errs = append(c.errs, &TagError{Type: rt, Err: ErrMissingType})
+	}
+	sortFields(p.fields)
+	linkHemispheres(p.fields)
+
+	if c.errs != nil {
+		p.err = c.errs
+	}
+	return p
+}
+
+// compiler holds the state for compiling the fields of a plan.
+type compiler struct {
+	top  reflect.Type
+	errs TagErrors
+}
+
+func (c *compiler) report(name, tag string, err error) {
+	c.errs = append(c.errs, &TagError{Type: c.top, Field: name, Tag: tag, Err: err})
+}
+
+// field returns the plan for a field of type typ with the given tag,
+// placed at the explicit position in the tag or otherwise at *next.
+// On return *next holds the position following the field. The returned
+// plan's position and width are valid even if the field is not valid,
+// so that later fields can be checked.
+func (c *compiler) field(name string, typ reflect.Type, tag string, next *int) (fp fieldPlan, ok bool) {
+	spec, err := parseTag(tag)
+	if err != nil {
+		c.report(name, tag, err)
+		fp = fieldPlan{pos: *next, width: 1}
+		*next++
+		return fp, false
+	}
+	if spec.at {
+		*next = spec.pos
+	}
+	fp, ok = c.fieldFor(name, typ, tag, spec)
+	fp.pos = *next
+	*next += fp.width
+	return fp, ok
+}
+
+// column is the sentence field layout of a struct field.
+type column struct {
+	name, tag  string
+	pos, width int
+	rest       bool
+}
+
+// layout checks that the columns of a struct with explicit field
+// positions do not overlap each other or the first start positions,
+// and that no column follows a rest column. It returns the width of
+// the struct, which is at least min.
+func (c *compiler) layout(cols []column, start, min int) (width int) {
+	sort.SliceStable(cols, func(i, j int) bool {
+		return cols[i].pos < cols[j].pos
+	})
+	end := start
+	var rest bool
+	for _, col := range cols {
+		switch {
+		case rest:
+			c.report(col.name, col.tag, ErrLateRest)
+		case col.pos < end:
+			c.report(col.name, col.tag, ErrPosition)
+		}
+		rest = rest || col.rest
+		if col.pos+col.width > end {
+			end = col.pos + col.width
+		}
+	}
+	if end < min {
+		end = min
+	}
+	return end
+}
+
+// implicit returns whether untagged fields of type typ are filled. These
+// are nested structs and types implementing FieldUnmarshaler.
+func implicit(typ reflect.Type) bool {
+	return nests(typ) || reflect.PtrTo(typ).Implements(fieldUnmarshalerType)
+}
+
+// nests returns whether typ is a struct type with tagged fields.
+func nests(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Tag.Get("nmea") != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPositions returns whether any field of the struct type rt
+// has an explicit sentence field position.
+func hasPositions(rt reflect.Type) bool {
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag := f.Tag.Get("nmea")
+		if tag == "" || f.Name == "Type" || tag == "checksum" {
+			continue
+		}
+		spec, err := parseTag(tag)
+		if err == nil && spec.at {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *compiler) fieldFor(name string, typ reflect.Type, tag string, spec tagSpec) (fp fieldPlan, ok bool) {
+	fp = fieldPlan{name: name, method: spec.method, width: 1}
+
+	if spec.constant != "" {
+		if spec.method != "" || spec.repeat != 0 || spec.rest {
+			c.report(name, tag, ErrTagSyntax)
+			return fp, false
+		}
+		m := constant(spec.constant)
+		fp.kind = scalarField
+		fp.method = "const"
+		fp.parse = m.parse
+		fp.format = m.format
+		fp.borrow = m.borrow
+		return fp, true
+	}
+	if spec.method == "" && spec.repeat == 0 && !spec.rest && isText(typ) {
+		spec.method = "text"
+	}
+	if spec.method == "text" && spec.repeat == 0 && !spec.rest {
+		m := textMethod()
+		switch {
+		case isText(typ):
+		case typ.Kind() == reflect.Ptr && isText(typ.Elem()):
+			m = optional(m)
+		case typ.Kind() == reflect.Array, typ.Kind() == reflect.Slice:
+			return c.repeated(name, typ, tag, spec)
+		default:
+			c.report(name, tag, ErrType)
+			return fp, false
+		}
+		fp.kind = scalarField
+		fp.method = "text"
+		fp.parse = m.parse
+		fp.format = m.format
+		return fp, true
+	}
+	if spec.rest {
+		return c.repeated(name, typ, tag, spec)
+	}
+	if spec.repeat == 0 && spec.method != "" {
+		m, ok := methodFor(spec.method)
+		if !ok {
+			c.report(name, tag, ErrUnknownMethod)
+			return fp, false
+		}
+		if m.span > 1 {
+			fp.width = m.span
+		}
+		lon := method{format: appendLongitude}
+		if canHold(m, typ) {
+			m = enumerated(m, typ)
+		} else {
+			switch {
+			case typ.Kind() == reflect.Ptr && canHold(m, typ.Elem()):
+				m = optional(enumerated(m, typ.Elem()))
+				lon = optional(lon)
+			case typ.Kind() == reflect.Array, typ.Kind() == reflect.Slice:
+				return c.repeated(name, typ, tag, spec)
+			default:
+				c.report(name, tag, ErrType)
+				return fp, false
+			}
+		}
+		fp.kind = scalarField
+		fp.parse = m.parse
+		fp.format = m.format
+		fp.borrow = m.borrow
+		fp.parseSpan = m.parseSpan
+		if spec.method == "latlon" {
+			fp.lonFormat = lon.format
+		}
+		return fp, true
+	}
+
+	if spec.repeat != 0 || typ.Kind() == reflect.Array {
+		return c.repeated(name, typ, tag, spec)
+	}
+
+	if typ.Kind() != reflect.Struct {
+		c.report(name, tag, ErrUnknownMethod)
+		return fp, false
+	}
+	fp.kind = structField
+	ok = true
+	explicit := hasPositions(typ)
+	var (
+		cols []column
+		next int
+	)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("nmea")
+		if tag == "" && !implicit(f.Type) {
+			if !explicit {
+				next++
+			}
+			continue
+		}
+		path := name + "." + f.Name
+		if f.Name == "Type" || tag == "checksum" {
+			c.report(path, tag, ErrTagSyntax)
+			ok = false
+			next++
+			continue
+		}
+		sub, subOK := c.field(path, f.Type, tag, &next)
+		if subOK {
+			sub.index = i
+			sub.name = f.Name
+			fp.fields = append(fp.fields, sub)
+		}
+		ok = ok && subOK
+		cols = append(cols, column{name: path, tag: tag, pos: sub.pos, width: sub.width, rest: sub.rest})
+	}
+	fp.width = next
+	if explicit {
+		n := len(c.errs)
+		fp.width = c.layout(cols, 0, fp.width)
+		ok = ok && len(c.errs) == n
+	}
+	sortFields(fp.fields)
+	linkHemispheres(fp.fields)
+	return fp, ok
+}
+
+// repeated returns the plan for an array or slice field.
+func (c *compiler) repeated(name string, typ reflect.Type, tag string, spec tagSpec) (fp fieldPlan, ok bool) {
+	fp = fieldPlan{name: name, method: spec.method, width: 1}
+	if spec.rest {
+		if typ.Kind() != reflect.Slice || spec.repeat != 0 {
+			c.report(name, tag, ErrTagSyntax)
+			return fp, false
+		}
+		if spec.method == "" && typ.Elem().Kind() != reflect.Struct && !isText(typ.Elem()) {
+			spec.method = "string"
+		}
+		elem, ok := c.fieldFor(name, typ.Elem(), tag, tagSpec{method: spec.method})
+		if !ok {
+			return fp, false
+		}
+		elem.name = ""
+		fp.kind = sliceField
+		fp.rest = true
+		fp.width = 0
+		fp.elem = &elem
+		return fp, true
+	}
+	switch typ.Kind() {
+	case reflect.Array:
+		if spec.repeat != 0 && spec.repeat != typ.Len() {
+			c.report(name, tag, ErrRepeat)
+			return fp, false
+		}
+		fp.kind = arrayField
+		fp.repeat = typ.Len()
+	case reflect.Slice:
+		if spec.repeat == 0 {
+			c.report(name, tag, ErrRepeat)
+			return fp, false
+		}
+		fp.kind = sliceField
+		fp.repeat = spec.repeat
+	default:
+		c.report(name, tag, ErrRepeat)
+		return fp, false
+	}
+	elem, ok := c.fieldFor(name, typ.Elem(), tag, tagSpec{method: spec.method})
+	fp.width = fp.repeat * elem.width
+	if !ok {
+		return fp, false
+	}
+	elem.name = ""
+	fp.elem = &elem
+	return fp, true
+}
+
+func sortFields(fields []fieldPlan) {
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].pos < fields[j].pos
+	})
+}
+
+// linkHemispheres links each "latlon" field in fields to a scalar field
+// in the following sentence field position, which may hold its
+// hemisphere. fields must be sorted by position.
+func linkHemispheres(fields []fieldPlan) {
+	for i := 0; i+1 < len(fields); i++ {
+		f := &fields[i]
+		next := fields[i+1]
+		if f.kind == scalarField && f.lonFormat != nil && next.kind == scalarField && next.pos == f.pos+1 {
+			f.hemi = i + 2
+		}
+	}
+}
+
+// constant returns a method for a field that must hold the value want.
+// Empty sentence fields are accepted and other values result in a
+// *ConstError. If the field is a settable string, it is set to the
+// parsed value. The value want is always formatted.
+func constant(want string) method {
+	parse := func(dst reflect.Value, field string) error {
+		if field != "" && field != want {
+			return &ConstError{Want: want, Got: field}
+		}
+		if dst.Kind() == reflect.String && dst.CanSet() {
+			if field != "" {
+				field = want
+			}
+			dst.SetString(field)
+		}
+		return nil
+	}
+	format := func(dst []byte, _ reflect.Value) ([]byte, error) {
+		return append(dst, want...), nil
+	}
+	return method{parse: parse, format: format, borrow: parse}
+}
+
+// optional returns a method that applies m to the element of a pointer
+// field. Empty sentence fields are parsed as nil pointers and nil pointers
+// are formatted as empty sentence fields.
+func optional(m method) method {
+	wrap := func(parse ParseFunc) ParseFunc {
+		if parse == nil {
+			return nil
+		}
+		return func(dst reflect.Value, field string) error {
+			if field == "" {
+				dst.Set(reflect.Zero(dst.Type()))
+				return nil
+			}
+			if dst.IsNil() {
+				dst.Set(reflect.New(dst.Type().Elem()))
+			}
+			return parse(dst.Elem(), field)
+		}
+	}
+	opt := method{parse: wrap(m.parse), borrow: wrap(m.borrow), span: m.span}
+	if m.parseSpan != nil {
+		opt.parseSpan = func(dst reflect.Value, fields []string) error {
+			if allEmpty(fields) {
+				dst.Set(reflect.Zero(dst.Type()))
+				return nil
+			}
+			if dst.IsNil() {
+				dst.Set(reflect.New(dst.Type().Elem()))
+			}
+			return m.parseSpan(dst.Elem(), fields)
+		}
+	}
+	if m.format != nil {
+		opt.format = func(dst []byte, src reflect.Value) ([]byte, error) {
+			if src.IsNil() {
+				for i := 1; i < m.span; i++ {
+					dst = append(dst, ',')
+				}
+				return dst, nil
+			}
+			return m.format(dst, src.Elem())
+		}
+	}
+	return opt
+}
+
+func allEmpty(fields []string) bool {
+	for _, f := range fields {
+		if f != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// matches returns whether the sentence type typ matches the plan's
+// Type tag.
+func (p *plan) matches(typ string) bool {
+	if p.re != nil {
+		return p.re.MatchString(typ)
+	}
+	if strings.HasPrefix(p.literal, anyTalker) {
+		return hasTalker(typ) && typ[2:] == p.literal[2:]
+	}
+	return typ == p.literal
+}
+
+// parse fills the fields of rv from the sentence fields. The first
+// element of fields must be the sentence type. If borrowed is true,
+// the field strings are not retained.
+func (p *plan) parse(rv reflect.Value, fields []string, sum int64, borrowed bool) error {
+	typ := fields[0]
+	if p.storeType {
+		if f := rv.Field(0); !borrowed || f.String() != typ {
+			if borrowed {
+				typ = cloneString(typ)
+			}
+			f.SetString(typ)
+		}
+	}
+	if !p.matches(typ) {
+		return ErrNMEAType
+	}
+
+	err := parseFields(rv, p.fields, fields, 0, borrowed)
+	if err != nil {
+		if borrowed {
+			typ = cloneString(typ)
+		}
+		err.Type = typ
+		return err
+	}
+
+	for _, i := range p.sums {
+		f := rv.Field(i)
+		switch f.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			f.SetInt(sum)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			f.SetUint(uint64(sum))
+		}
+	}
+	return nil
+}
+
+// parseFields fills the fields of the struct rv described by plans from
+// the sentence fields, starting at the sentence field position base. The
+// Type field of a returned error is not set.
+func parseFields(rv reflect.Value, plans []fieldPlan, fields []string, base int, borrowed bool) *ParseError {
+	for i := range plans {
+		f := &plans[i]
+		if base+f.pos >= len(fields) {
+			break
+		}
+		err := f.parseValue(rv.Field(f.index), fields, base+f.pos, borrowed)
+		if err != nil {
+			err.Field = joinPath(f.name, err.Field)
+			return err
+		}
+	}
+	return nil
+}
+
+// parseValue fills dst from the sentence fields starting at pos. The
+// Type field of a returned error is not set and the Field field is
+// relative to f.
+func (f *fieldPlan) parseValue(dst reflect.Value, fields []string, pos int, borrowed bool) *ParseError {
+	switch f.kind {
+	case structField:
+		return parseFields(dst, f.fields, fields, pos, borrowed)
+
+	case arrayField:
+		for i := 0; i < f.repeat; i++ {
+			start := pos + i*f.elem.width
+			if start >= len(fields) {
+				break
+			}
+			err := f.elem.parseValue(dst.Index(i), fields, start, borrowed)
+			if err != nil {
+				err.Field = joinPath("["+strconv.Itoa(i)+"]", err.Field)
+				return err
+			}
+		}
+		return nil
+
+	case sliceField:
+		// The length of a rest slice is the number of element
+		// groups remaining in the sentence. Otherwise it is the
+		// number of element groups up to the last group with a
+		// non-empty field.
+		n := 0
+		if f.rest {
+			n = (len(fields) - pos + f.elem.width - 1) / f.elem.width
+		}
+		for i := 0; i < f.repeat; i++ {
+			start := pos + i*f.elem.width
+			if start >= len(fields) {
+				break
+			}
+			end := start + f.elem.width
+			if end > len(fields) {
+				end = len(fields)
+			}
+			if !allEmpty(fields[start:end]) {
+				n = i + 1
+			}
+		}
+		if dst.Cap() < n {
+			cap := f.repeat
+			if f.rest {
+				cap = n
+			}
+			dst.Set(reflect.MakeSlice(dst.Type(), n, cap))
+		} else {
+			dst.SetLen(n)
+		}
+		for i := 0; i < n; i++ {
+			err := f.elem.parseValue(dst.Index(i), fields, pos+i*f.elem.width, borrowed)
+			if err != nil {
+				err.Field = joinPath("["+strconv.Itoa(i)+"]", err.Field)
+				return err
+			}
+		}
+		return nil
+	}
+
+	if f.width > 1 {
+		end := pos + f.width
+		if end > len(fields) {
+			end = len(fields)
+		}
+		span := fields[pos:end]
+		err := f.parseSpan(dst, span)
+		if err != nil {
+			if borrowed {
+				// Parse again with copies so that the
+				// returned error does not hold the fields.
+				clones := make([]string, len(span))
+				for i, s := range span {
+					clones[i] = cloneString(s)
+				}
+				span = clones
+				err = f.parseSpan(dst, span)
+			}
+			return newParseError(f, pos, strings.Join(span, ","), err)
+		}
+		return nil
+	}
+
+	field := fields[pos]
+	var err error
+	switch {
+	case !borrowed:
+		err = f.parse(dst, field)
+	case f.borrow != nil:
+		err = f.borrow(dst, field)
+		if err != nil {
+			// Parse again with a copy so that the
+			// returned error does not hold the field.
+			field = cloneString(field)
+			err = f.parse(dst, field)
+		}
+	default:
+		field = cloneString(field)
+		err = f.parse(dst, field)
+	}
+	if err != nil {
+		return newParseError(f, pos, field, err)
+	}
+	return nil
+}
+
+// joinPath returns the field path of child relative to parent.
+func joinPath(parent, child string) string {
+	switch {
+	case child == "":
+		return parent
+	case parent == "", child[0] == '[':
+		return parent + child
+	default:
+		return parent + "." + child
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Position is a signed decimal degree position. Southern latitudes
+// and western longitudes are negative.
+type Position struct {
+	Lat, Lon float64
+}
+
+// signed returns v negated if hemi is the negative hemisphere, neg.
+func signed(v float64, hemi, neg string) float64 {
+	if hemi == neg {
+		return -v
+	}
+	return v
+}
+
+// LatLon is a latitude and longitude block of four sentence fields. It may
+// be embedded in, or be a field of, a destination struct to share the block
+// between sentence types.
+type LatLon struct {
+	Latitude   float64 `nmea:"latlon"`
+	NorthSouth string  `nmea:"string"`
+	Longitude  float64 `nmea:"latlon"`
+	EastWest   string  `nmea:"string"`
+}
+
+// Position returns the signed position of the block.
+func (m LatLon) Position() Position {
+	return Position{Lat: signed(m.Latitude, m.NorthSouth, "S"), Lon: signed(m.Longitude, m.EastWest, "W")}
+}
+
+// Position returns the signed position of the BWC waypoint.
+func (m BWC) Position() Position {
+	return LatLon{m.Latitude, m.NorthSouth, m.Longitude, m.EastWest}.Position()
+}
+
+// Position returns the signed position of the GGA fix.
+func (m GGA) Position() Position {
+	return LatLon{m.Latitude, m.NorthSouth, m.Longitude, m.EastWest}.Position()
+}
+
+// Position returns the signed position of the GLL fix.
+func (m GLL) Position() Position {
+	return LatLon{m.Latitude, m.NorthSouth, m.Longitude, m.EastWest}.Position()
+}
+
+// Position returns the signed position of the GNS fix.
+func (m GNS) Position() Position {
+	return LatLon{m.Latitude, m.NorthSouth, m.Longitude, m.EastWest}.Position()
+}
+
+// Position returns the signed position of the RMB destination.
+func (m RMB) Position() Position {
+	return LatLon{m.Latitude, m.NorthSouth, m.Longitude, m.EastWest}.Position()
+}
+
+// Position returns the signed position of the RMC fix.
+func (m RMC) Position() Position {
+	return LatLon{m.Latitude, m.NorthSouth, m.Longitude, m.EastWest}.Position()
+}
+
+// Position returns the signed position of the TRF fix.
+func (m TRF) Position() Position {
+	return LatLon{m.Latitude, m.NorthSouth, m.Longitude, m.EastWest}.Position()
+}
+
+// Position returns the signed position of the WPL waypoint.
+func (m WPL) Position() Position {
+	return LatLon{m.Latitude, m.NorthSouth, m.Longitude, m.EastWest}.Position()
+}
+
+// parseDegrees returns the signed decimal degrees value of an NMEA
+// [d]ddmm.mm value and hemisphere pair. A value without a hemisphere
+// is an error.
+func parseDegrees(value, hemi, pos, neg string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if hemi != pos && hemi != neg {
+		return 0, ErrHemisphere
+	}
+	val, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	return signed(degrees(val), hemi, neg), nil
+}
+
+// spanField returns the ith element of fields or the empty string if
+// i is beyond the end of fields.
+func spanField(fields []string, i int) string {
+	if i < len(fields) {
+		return fields[i]
+	}
+	return ""
+}
+
+func setLat(dst reflect.Value, fields []string) error {
+	return setSignedDegrees(dst, fields, "N", "S")
+}
+
+func setLon(dst reflect.Value, fields []string) error {
+	return setSignedDegrees(dst, fields, "E", "W")
+}
+
+func setSignedDegrees(dst reflect.Value, fields []string, pos, neg string) error {
+	switch dst.Kind() {
+	default:
+		return ErrType
+	case reflect.Float64, reflect.Float32:
+		val, err := parseDegrees(spanField(fields, 0), spanField(fields, 1), pos, neg)
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(val)
+	}
+	return nil
+}
+
+var positionType = reflect.TypeOf(Position{})
+
+func setPosition(dst reflect.Value, fields []string) error {
+	if dst.Type() != positionType {
+		return ErrType
+	}
+	lat, err := parseDegrees(spanField(fields, 0), spanField(fields, 1), "N", "S")
+	if err != nil {
+		return err
+	}
+	lon, err := parseDegrees(spanField(fields, 2), spanField(fields, 3), "E", "W")
+	if err != nil {
+		return err
+	}
+	dst.Field(0).SetFloat(lat)
+	dst.Field(1).SetFloat(lon)
+	return nil
+}
+
+func appendLat(dst []byte, src reflect.Value) ([]byte, error) {
+	switch src.Kind() {
+	default:
+		return dst, ErrType
+	case reflect.Float64, reflect.Float32:
+		return appendSignedDegrees(dst, src.Float(), 2, 'N', 'S'), nil
+	}
+}
+
+func appendLon(dst []byte, src reflect.Value) ([]byte, error) {
+	switch src.Kind() {
+	default:
+		return dst, ErrType
+	case reflect.Float64, reflect.Float32:
+		return appendSignedDegrees(dst, src.Float(), 3, 'E', 'W'), nil
+	}
+}
+
+func appendPosition(dst []byte, src reflect.Value) ([]byte, error) {
+	if src.Type() != positionType {
+		return dst, ErrType
+	}
+	dst = appendSignedDegrees(dst, src.Field(0).Float(), 2, 'N', 'S')
+	dst = append(dst, ',')
+	return appendSignedDegrees(dst, src.Field(1).Float(), 3, 'E', 'W'), nil
+}
+
+// appendSignedDegrees appends the NMEA value and hemisphere fields for the
+// signed decimal degrees value v.
+func appendSignedDegrees(dst []byte, v float64, degDigits int, pos, neg byte) []byte {
+	hemi := pos
+	if v < 0 {
+		hemi = neg
+		v = -v
+	}
+	dst = appendDegrees(dst, v, degDigits)
+	return append(dst, ',', hemi)
+}
@@ -0,0 +1,153 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type signedGLL struct {
+	Type string `nmea:"/G[LNP]GLL/"`
+
+	Latitude  float64   `nmea:"lat"`
+	Longitude float64   `nmea:"lon"`
+	Timestamp time.Time `nmea:"time"`
+	Status    string    `nmea:"string"`
+
+	Checksum byte `nmea:"checksum"`
+}
+
+type positionGLL struct {
+	Type string `nmea:"/G[LNP]GLL/"`
+
+	Position  *Position `nmea:"position"`
+	Timestamp time.Time `nmea:"time"`
+	Status    string    `nmea:"string"`
+}
+
+var positionTests = []struct {
+	sentence string
+	want     Position
+}{
+	{sentence: "$GPGLL,3751.65,S,14507.36,E,225444,A", want: Position{Lat: -37.86083333333333, Lon: 145.12266666666667}},
+	{sentence: "$GPGLL,4916.45,N,12311.12,W,225444,A", want: Position{Lat: 49.27416666666666, Lon: -123.18533333333335}},
+	{sentence: "$GPGLL,5300.97914,N,00259.98174,E,125926,A", want: Position{Lat: 53.01631900000001, Lon: 2.9996956666666668}},
+}
+
+func TestSignedLatLon(t *testing.T) {
+	for _, test := range positionTests {
+		var gll GLL
+		err := ParseTo(&gll, test.sentence)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if got := gll.Position(); got != test.want {
+			t.Errorf("unexpected GLL position for %q: got:%v want:%v", test.sentence, got, test.want)
+		}
+
+		var signed signedGLL
+		err = ParseTo(&signed, test.sentence)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if got := (Position{Lat: signed.Latitude, Lon: signed.Longitude}); got != test.want {
+			t.Errorf("unexpected signed position for %q: got:%v want:%v", test.sentence, got, test.want)
+		}
+		if signed.Status != "A" {
+			t.Errorf("unexpected status after signed fields: got:%q want:%q", signed.Status, "A")
+		}
+
+		var pos positionGLL
+		err = ParseTo(&pos, test.sentence)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if pos.Position == nil || *pos.Position != test.want {
+			t.Errorf("unexpected position for %q: got:%v want:%v", test.sentence, pos.Position, test.want)
+		}
+
+		// Round trip through Marshal.
+		s, err := Marshal(pos)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		var got positionGLL
+		err = ParseTo(&got, s)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if got.Position == nil || !closePosition(*got.Position, test.want) {
+			t.Errorf("unexpected round trip position for %q via %q: got:%v want:%v", test.sentence, s, got.Position, test.want)
+		}
+	}
+}
+
+func closePosition(a, b Position) bool {
+	const tol = 1e-7
+	return abs(a.Lat-b.Lat) < tol && abs(a.Lon-b.Lon) < tol
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func TestMarshalPosition(t *testing.T) {
+	got, err := Marshal(positionGLL{
+		Type:     "GPGLL",
+		Position: &Position{Lat: -37.86083333333333, Lon: 2.9996956666666668},
+		Status:   "A",
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	const want = "$GPGLL,3751.65,S,00259.98174,E,,A*09"
+	if got != want {
+		t.Errorf("unexpected result:\ngot: %q\nwant:%q", got, want)
+	}
+
+	got, err = Marshal(positionGLL{Type: "GPGLL"})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	const wantEmpty = "$GPGLL,,,,,,*50"
+	if got != wantEmpty {
+		t.Errorf("unexpected result:\ngot: %q\nwant:%q", got, wantEmpty)
+	}
+}
+
+var badHemisphereTests = []struct {
+	sentence string
+	index    int
+	value    string
+}{
+	{sentence: "$GPGLL,3751.65,X,14507.36,E,225444,A", index: 1, value: "3751.65,X"},
+	{sentence: "$GPGLL,4916.45,,12311.12,E,225444,A", index: 1, value: "4916.45,"},
+	{sentence: "$GPGLL,4916.45,N,12311.12,,225444,A", index: 3, value: "12311.12,"},
+}
+
+func TestBadHemisphere(t *testing.T) {
+	for _, test := range badHemisphereTests {
+		for _, borrowed := range []bool{false, true} {
+			var err error
+			if borrowed {
+				err = ParseToBytes(&signedGLL{}, []byte(test.sentence))
+			} else {
+				err = ParseTo(&signedGLL{}, test.sentence)
+			}
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected *ParseError for %q: got:%T", test.sentence, err)
+			}
+			if perr.Err != ErrHemisphere || perr.Index != test.index || perr.Value != test.value {
+				t.Errorf("unexpected error for %q: %#v", test.sentence, perr)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"strings"
+	"sync"
+)
+
+// Query is a NMEA 0183 query sentence, a request from a listener for a
+// talker to transmit a specific sentence. For example, in
+//  $CCGPQ,GGA*2B
+// the listener CC requests a GGA sentence from the talker GP.
+type Query struct {
+	Requester string // Requester is the talker ID of the listener.
+	Target    string // Target is the talker ID of the queried talker.
+	Formatter string // Formatter is the requested sentence formatter.
+}
+
+// queryType is the registration key for query sentences from any
+// listener to any talker.
+const queryType = anyTalker + anyTalker + "Q"
+
+// isQuery returns whether typ is a query sentence type.
+func isQuery(typ string) bool {
+	return len(typ) == 5 && typ[4] == 'Q' && hasTalker(typ) && hasTalker(typ[2:])
+}
+
+// Type returns the sentence type of the query.
+func (q Query) Type() SentenceType {
+	return SentenceType(q.Requester + q.Target + "Q")
+}
+
+// Requested returns the sentence type that is requested by the query.
+func (q Query) Requested() SentenceType {
+	return SentenceType(q.Target + q.Formatter)
+}
+
+// UnmarshalNMEA implements the Unmarshaler interface.
+func (q *Query) UnmarshalNMEA(fields []string) error {
+	if !isQuery(fields[0]) {
+		return ErrNMEAType
+	}
+	if len(fields) < 2 || fields[1] == "" {
+		return ErrTooShort
+	}
+	*q = Query{
+		Requester: fields[0][:2],
+		Target:    fields[0][2:4],
+		Formatter: fields[1],
+	}
+	return nil
+}
+
+// MarshalNMEA implements the Marshaler interface.
+func (q Query) MarshalNMEA() ([]string, error) {
+	typ := string(q.Type())
+	if !isQuery(typ) || q.Formatter == "" {
+		return nil, ErrNMEAType
+	}
+	return []string{typ, q.Formatter}, nil
+}
+
+// Responder holds the most recent sentences sent by a talker-side service
+// so that it can answer queries. A Responder is safe for concurrent use.
+type Responder struct {
+	mu     sync.RWMutex
+	latest map[string]string
+}
+
+// NewResponder returns a new Responder holding no sentences.
+func NewResponder() *Responder {
+	return &Responder{latest: make(map[string]string)}
+}
+
+// Update records the NMEA 0183 sentence encoding of v as the latest
+// sentence of its type. The value v is encoded by Marshal. The sentence
+// type of v must have a talker ID.
+func (r *Responder) Update(v interface{}) error {
+	sentence, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	typ := sentence[1:strings.IndexAny(sentence, ",*")]
+	if !hasTalker(typ) || strings.HasPrefix(typ, anyTalker) {
+		return ErrNMEAType
+	}
+	r.mu.Lock()
+	r.latest[typ] = sentence
+	r.mu.Unlock()
+	return nil
+}
+
+// Respond returns the latest sentence recorded by r that answers the query
+// q, and whether there is one. The returned sentence does not include a
+// line terminator.
+func (r *Responder) Respond(q Query) (sentence string, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sentence, ok = r.latest[string(q.Requested())]
+	return sentence, ok
+}
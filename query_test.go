@@ -0,0 +1,99 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"errors"
+	"testing"
+)
+
+var queryTests = []struct {
+	sentence string
+	want     Query
+	err      error
+}{
+	{
+		sentence: "$CCGPQ,GGA*2B",
+		want:     Query{Requester: "CC", Target: "GP", Formatter: "GGA"},
+	},
+	{
+		sentence: "$ECGPQ,RMC*30",
+		want:     Query{Requester: "EC", Target: "GP", Formatter: "RMC"},
+	},
+	{
+		sentence: "$CCGPQ,*6A",
+		err:      ErrTooShort,
+	},
+	{
+		sentence: "$GPGGA,123519",
+		err:      ErrNMEAType,
+	},
+}
+
+func TestQuery(t *testing.T) {
+	for _, test := range queryTests {
+		var got Query
+		err := ParseTo(&got, test.sentence)
+		if !errors.Is(err, test.err) {
+			t.Errorf("unexpected error for %q: got:%v want:%v", test.sentence, err, test.err)
+		}
+		if got != test.want {
+			t.Errorf("unexpected result for %q: got:%+v want:%+v", test.sentence, got, test.want)
+		}
+		if err != nil {
+			continue
+		}
+
+		v, err := Parse(test.sentence)
+		if err != nil {
+			t.Errorf("unexpected error from Parse for %q: %v", test.sentence, err)
+		}
+		if v != test.want {
+			t.Errorf("unexpected result from Parse for %q: got:%+v want:%+v", test.sentence, v, test.want)
+		}
+
+		s, err := Marshal(got)
+		if err != nil {
+			t.Errorf("unexpected error marshaling %+v: %v", got, err)
+		}
+		if s != test.sentence {
+			t.Errorf("unexpected sentence for %+v: got:%q want:%q", got, s, test.sentence)
+		}
+	}
+
+	_, err := Marshal(Query{Requester: "CC", Target: "P", Formatter: "GGA"})
+	if err != ErrNMEAType {
+		t.Errorf("unexpected error for invalid query: got:%v want:%v", err, ErrNMEAType)
+	}
+}
+
+func TestResponder(t *testing.T) {
+	r := NewResponder()
+	q := Query{Requester: "CC", Target: "GP", Formatter: "HDT"}
+	_, ok := r.Respond(q)
+	if ok {
+		t.Errorf("unexpected response from empty responder")
+	}
+
+	err := r.Update(HDT{Type: "GPHDT", Heading: 274.07})
+	if err != nil {
+		t.Fatalf("unexpected error updating responder: %v", err)
+	}
+	got, ok := r.Respond(q)
+	const want = "$GPHDT,274.07,T*03"
+	if !ok || got != want {
+		t.Errorf("unexpected response: got:%q ok:%t want:%q ok:true", got, ok, want)
+	}
+
+	_, ok = r.Respond(Query{Requester: "CC", Target: "II", Formatter: "HDT"})
+	if ok {
+		t.Errorf("unexpected response for other talker")
+	}
+
+	err = r.Update(HDT{})
+	if err != ErrNMEAType {
+		t.Errorf("unexpected error for wildcard type: got:%v want:%v", err, ErrNMEAType)
+	}
+}
@@ -0,0 +1,13 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build race
+// +build race
+
+package nmea
+
+// raceEnabled is whether the race detector is enabled. The race
+// detector randomly drops sync.Pool items, so allocation tests are
+// not reliable when it is enabled.
+const raceEnabled = true
@@ -0,0 +1,244 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Registry holds a set of NMEA 0183 sentence type registrations used to
+// select destination types for parsing. The zero value is an empty registry
+// ready to use. A Registry is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	types    map[string]interface{}
+	fallback bool
+	options  ParseOptions
+}
+
+// NewRegistry returns a new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[string]interface{})}
+}
+
+// NewDefaultRegistry returns a new Registry holding the default type
+// registrations listed in the documentation for Register.
+func NewDefaultRegistry() *Registry {
+	return &Registry{types: map[string]interface{}{
+		"--BOD": BOD{},
+		"--BWC": BWC{},
+		"--GGA": GGA{},
+		"--GLL": GLL{},
+		"--GNS": GNS{},
+		"--GSA": GSA{},
+		"--GSV": GSV{},
+		"--HDT": HDT{},
+		"--R00": R00{},
+		"--RMA": RMA{},
+		"--RMB": RMB{},
+		"--RMC": RMC{},
+		"--RTE": RTE{},
+		"--STN": STN{},
+		"--THS": THS{},
+		"--TRF": TRF{},
+		"--VBW": VBW{},
+		"--VDM": VDMVDO{},
+		"--VDO": VDMVDO{},
+		"--VTG": VTG{},
+		"--WPL": WPL{},
+		"--XTE": XTE{},
+		"--ZDA": ZDA{},
+		"PGRME": RME{},
+		"PGRMM": RMM{},
+		"PGRMZ": RMZ{},
+		"PSLIB": LIB{},
+		"----Q": Query{},
+	}}
+}
+
+// Register registers the NMEA 0183 type to be parsed into the given
+// destination type, dst, in the registry. Unless a pointer to dst
+// implements Unmarshaler, the kind of dst must be a struct and its
+// "nmea" tags must be valid according to Validate, otherwise Register
+// will panic. Calling Register with an already
+// registered type will overwrite the existing registration. If dst
+// is nil, the type will be deregistered.
+func (r *Registry) Register(typ string, dst interface{}) {
+	if dst == nil {
+		r.Deregister(typ)
+		return
+	}
+	if !isUnmarshaler(reflect.TypeOf(dst)) {
+		if reflect.TypeOf(dst).Kind() != reflect.Struct {
+			panic(ErrNotStruct)
+		}
+		if err := Validate(dst); err != nil {
+			panic(err)
+		}
+	}
+	r.mu.Lock()
+	if r.types == nil {
+		r.types = make(map[string]interface{})
+	}
+	r.types[typ] = dst
+	r.mu.Unlock()
+}
+
+// Deregister removes the registration for the NMEA 0183 type from the
+// registry.
+func (r *Registry) Deregister(typ string) {
+	r.mu.Lock()
+	delete(r.types, typ)
+	r.mu.Unlock()
+}
+
+// Lookup returns the destination value registered for the NMEA 0183 type
+// and whether the type is registered. If typ has a talker ID and is not
+// registered, the registration for any talker, "--" followed by the
+// formatter of typ, is returned. If typ is a query sentence type and
+// neither is registered, the registration for any query, "----Q", is
+// returned.
+func (r *Registry) Lookup(typ string) (dst interface{}, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	dst, ok = r.types[typ]
+	if !ok && hasTalker(typ) {
+		dst, ok = r.types[anyTalker+typ[2:]]
+	}
+	if !ok && isQuery(typ) {
+		dst, ok = r.types[queryType]
+	}
+	return dst, ok
+}
+
+// SetFallback sets whether sentences with types that are not registered
+// are parsed into a Sentence by Parse and ParseBytes. If fallback is false,
+// as it is for a new Registry, these sentences result in ErrNotRegistered.
+func (r *Registry) SetFallback(fallback bool) {
+	r.mu.Lock()
+	r.fallback = fallback
+	r.mu.Unlock()
+}
+
+// SetParseOptions sets the options used by Parse, ParseBytes, ParseTo and
+// ParseToBytes. A new Registry uses the zero ParseOptions.
+func (r *Registry) SetParseOptions(opts ParseOptions) {
+	r.mu.Lock()
+	r.options = opts
+	r.mu.Unlock()
+}
+
+// parseOptions returns the parse options and fallback setting of r.
+func (r *Registry) parseOptions() (opts ParseOptions, fallback bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.options, r.fallback
+}
+
+// Types returns the sorted list of NMEA 0183 types registered in the
+// registry.
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	types := make([]string, 0, len(r.types))
+	for typ := range r.types {
+		types = append(types, typ)
+	}
+	r.mu.RUnlock()
+	sort.Strings(types)
+	return types
+}
+
+// Parse parses a raw NMEA 0183 sentence and fills the fields of a destination
+// struct registered in r with the data contained within the sentence and
+// returns it. Checksums are handled according to the parse options of r.
+// Sentences with unregistered types are returned as a Sentence if r has
+// fallback set, otherwise ErrNotRegistered is returned.
+func (r *Registry) Parse(sentence string) (interface{}, error) {
+	return r.parse(sentence, false)
+}
+
+// ParseBytes is like Parse but parses a sentence held in a byte slice.
+// The sentence is not retained after ParseBytes returns.
+func (r *Registry) ParseBytes(sentence []byte) (interface{}, error) {
+	return r.parse(unsafeString(sentence), true)
+}
+
+// ParseTo is like the package-level ParseTo function but uses the parse
+// options of r.
+func (r *Registry) ParseTo(dst interface{}, sentence string) error {
+	opts, _ := r.parseOptions()
+	return parseTo(dst, sentence, opts, false)
+}
+
+// ParseToBytes is like the package-level ParseToBytes function but uses
+// the parse options of r.
+func (r *Registry) ParseToBytes(dst interface{}, sentence []byte) error {
+	opts, _ := r.parseOptions()
+	return parseTo(dst, unsafeString(sentence), opts, true)
+}
+
+// ParseWithTagBlock is like Parse but also returns the parameters of any
+// tag blocks preceding the sentence, as described for ParseTagBlock.
+func (r *Registry) ParseWithTagBlock(sentence string) (TagBlock, interface{}, error) {
+	tb, sentence, err := r.ParseTagBlock(sentence)
+	if err != nil {
+		return TagBlock{}, nil, err
+	}
+	v, err := r.Parse(sentence)
+	return tb, v, err
+}
+
+// ParseTagBlock is like the package-level ParseTagBlock function but
+// does not check tag block checksums if the parse options of r ignore
+// checksums.
+func (r *Registry) ParseTagBlock(s string) (tb TagBlock, sentence string, err error) {
+	opts, _ := r.parseOptions()
+	return parseTagBlock(s, opts.Checksum)
+}
+
+// parse implements Parse and ParseBytes. If borrowed is true,
+// sentence must not be retained after parse returns.
+func (r *Registry) parse(sentence string, borrowed bool) (interface{}, error) {
+	opts, fallback := r.parseOptions()
+	f, err := splitSentence(sentence, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = f.verify(opts.Checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := getFields()
+	defer putFields(fields)
+	*fields = splitFields((*fields)[:0], f.body)
+
+	dst, ok := r.Lookup((*fields)[0])
+	if !ok {
+		if !fallback {
+			return nil, ErrNotRegistered
+		}
+		return newSentence(f, *fields, opts.Checksum, borrowed)
+	}
+
+	typ := reflect.TypeOf(dst)
+	if isUnmarshaler(typ) {
+		rv := reflect.New(typ)
+		err = unmarshal(rv.Interface().(Unmarshaler), *fields, borrowed)
+		return rv.Elem().Interface(), f.report(opts.Checksum, err)
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	p := planFor(typ)
+	if p.err != nil {
+		return nil, p.err
+	}
+	rv := reflect.New(typ).Elem()
+	err = p.parse(rv, *fields, int64(f.want), borrowed)
+	return rv.Interface(), f.report(opts.Checksum, err)
+}
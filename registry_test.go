@@ -0,0 +1,137 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type shortRMC struct {
+	Type string `nmea:"GPRMC"`
+
+	Time time.Time `nmea:"time"`
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	if types := r.Types(); len(types) != 0 {
+		t.Errorf("unexpected types in new registry: %v", types)
+	}
+	_, err := r.Parse("$GPRMC,081836,A,3751.65,S,14507.36,E,000.0,360.0,130998,011.3,E*62")
+	if err != ErrNotRegistered {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrNotRegistered)
+	}
+
+	r.Register("GPRMC", shortRMC{})
+	got, err := r.Parse("$GPRMC,081836,A,3751.65,S,14507.36,E,000.0,360.0,130998,011.3,E*62")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	want := shortRMC{Type: "GPRMC", Time: time.Date(0, 1, 1, 8, 18, 36, 0, time.UTC)}
+	if got != want {
+		t.Errorf("unexpected result:\ngot: %#v\nwant:%#v", got, want)
+	}
+	if dst, ok := r.Lookup("GPRMC"); !ok || reflect.TypeOf(dst) != reflect.TypeOf(shortRMC{}) {
+		t.Errorf("unexpected lookup result: %T %t", dst, ok)
+	}
+	if types := r.Types(); !reflect.DeepEqual(types, []string{"GPRMC"}) {
+		t.Errorf("unexpected types: %v", types)
+	}
+
+	// The default registry must not be affected.
+	got, err = Parse("$GPRMC,081836,A,3751.65,S,14507.36,E,000.0,360.0,130998,011.3,E*62")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, ok := got.(RMC); !ok {
+		t.Errorf("unexpected type from default registry: %T", got)
+	}
+
+	r.Deregister("GPRMC")
+	if _, ok := r.Lookup("GPRMC"); ok {
+		t.Error("unexpected registration after deregistration")
+	}
+
+	var zero Registry
+	zero.Register("GPHDT", HDT{})
+	if _, err := zero.Parse("$GPHDT,1,T*2A"); err != nil {
+		t.Errorf("unexpected error from zero registry: %v", err)
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	r := NewDefaultRegistry()
+	for _, test := range parseTests {
+		got, err := r.Parse(test.sentence)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		want := reflect.ValueOf(test.want).Elem().Interface()
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected result:\ngot: %#v\nwant:%#v", got, want)
+		}
+	}
+}
+
+func TestAnyTalker(t *testing.T) {
+	r := NewDefaultRegistry()
+	for _, sentence := range []string{
+		"$GAGGA,123519,4807.038,N,01131.000,W,1,2,3,4,M,5,M,,",
+		"$GBGGA,123519,4807.038,N,01131.000,W,1,2,3,4,M,5,M,,",
+		"$BDGGA,123519,4807.038,N,01131.000,W,1,2,3,4,M,5,M,,",
+		"$GQGGA,123519,4807.038,N,01131.000,W,1,2,3,4,M,5,M,,",
+		"$GIGGA,123519,4807.038,N,01131.000,W,1,2,3,4,M,5,M,,",
+	} {
+		got, err := r.Parse(sentence)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", sentence, err)
+			continue
+		}
+		gga, ok := got.(GGA)
+		if !ok {
+			t.Errorf("unexpected type for %q: %T", sentence, got)
+			continue
+		}
+		talker, formatter := SplitType(gga.Type)
+		if talker != sentence[1:3] || formatter != "GGA" {
+			t.Errorf("unexpected talker and formatter for %q: %q %q", sentence, talker, formatter)
+		}
+	}
+	for _, sentence := range []string{"$IIHDT,274.07,T", "$HCHDT,274.07,T", "$SDHDT,274.07,T"} {
+		got, err := r.Parse(sentence)
+		if _, ok := got.(HDT); !ok || err != nil {
+			t.Errorf("unexpected result for %q: %T %v", sentence, got, err)
+		}
+	}
+
+	// Talker-specific registrations take priority.
+	r.Register("GPRMC", shortRMC{})
+	got, err := r.Parse("$GPRMC,081836,A,3751.65,S,14507.36,E,000.0,360.0,130998,011.3,E*62")
+	if _, ok := got.(shortRMC); !ok || err != nil {
+		t.Errorf("unexpected result for specific registration: %T %v", got, err)
+	}
+	got, err = r.Parse("$GNRMC,081836,A,3751.65,S,14507.36,E,000.0,360.0,130998,011.3,E")
+	if _, ok := got.(RMC); !ok || err != nil {
+		t.Errorf("unexpected result for any talker registration: %T %v", got, err)
+	}
+
+	// Proprietary sentences do not match any talker registrations.
+	r.Register("--XYZ", HDT{})
+	if _, ok := r.Lookup("PGXYZ"); ok {
+		t.Error("unexpected proprietary match for any talker registration")
+	}
+
+	_, err = Marshal(GGA{})
+	if err != ErrNMEAType {
+		t.Errorf("unexpected error marshaling without talker: got:%v want:%v", err, ErrNMEAType)
+	}
+	s, err := Marshal(HDT{Type: "HCHDT", Heading: 274.07})
+	const want = "$HCHDT,274.07,T*1F"
+	if err != nil || s != want {
+		t.Errorf("unexpected marshal result: got:%q %v want:%q", s, err, want)
+	}
+}
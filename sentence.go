@@ -0,0 +1,166 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import "strings"
+
+// Sentence is a generic NMEA 0183 sentence. It holds the unparsed
+// fields of sentences of any type.
+type Sentence struct {
+	// Start is the start delimiter of the
+	// sentence, '$' or '!'.
+	Start byte
+
+	// Talker is the talker ID of the sentence, or
+	// "P" for proprietary sentences, and Formatter
+	// is the remainder of the sentence type.
+	Talker    string
+	Formatter string
+
+	// Fields holds the data fields of the
+	// sentence following the sentence type.
+	Fields []string
+
+	// HasChecksum is whether the sentence has a
+	// checksum. If it does, Checksum is the value
+	// given in the sentence and ChecksumValid is
+	// whether it matches the sentence's bytes.
+	HasChecksum   bool
+	Checksum      byte
+	ChecksumValid bool
+}
+
+// ParseSentence parses a raw NMEA 0183 sentence into a Sentence without
+// interpreting its fields. If the sentence has a checksum that does not
+// match the sentence's bytes, the parsed Sentence is returned along with
+// a *ChecksumError.
+func ParseSentence(sentence string) (Sentence, error) {
+	return parseSentence(sentence, ParseOptions{}, false)
+}
+
+// ParseSentenceBytes is like ParseSentence but parses a sentence held in
+// a byte slice. The sentence is not retained after ParseSentenceBytes
+// returns.
+func ParseSentenceBytes(sentence []byte) (Sentence, error) {
+	return parseSentence(unsafeString(sentence), ParseOptions{}, true)
+}
+
+// parseSentence implements ParseSentence and ParseSentenceBytes using the
+// options in opts. If borrowed is true, sentence must not be retained after
+// parseSentence returns.
+func parseSentence(sentence string, opts ParseOptions, borrowed bool) (Sentence, error) {
+	f, err := splitSentence(sentence, opts)
+	if err != nil {
+		return Sentence{}, err
+	}
+	err = f.verify(opts.Checksum)
+	if err != nil {
+		return Sentence{}, err
+	}
+	fields := getFields()
+	defer putFields(fields)
+	*fields = splitFields((*fields)[:0], f.body)
+	return newSentence(f, *fields, opts.Checksum, borrowed)
+}
+
+// newSentence returns a Sentence holding copies of the fields of the sentence
+// f. The Sentence is returned with any checksum error reported under policy.
+// If borrowed is true, the fields are cloned.
+func newSentence(f frame, fields []string, policy ChecksumPolicy, borrowed bool) (Sentence, error) {
+	s := Sentence{
+		Start:         f.start,
+		Fields:        make([]string, len(fields)-1),
+		HasChecksum:   f.hasSum,
+		Checksum:      f.want,
+		ChecksumValid: f.hasSum && f.sum == f.want,
+	}
+	typ := fields[0]
+	if borrowed {
+		typ = cloneString(typ)
+	}
+	s.Talker, s.Formatter = SplitType(typ)
+	for i, field := range fields[1:] {
+		if borrowed {
+			field = cloneString(field)
+		}
+		s.Fields[i] = field
+	}
+	return s, f.report(policy, nil)
+}
+
+// SentenceType is a NMEA 0183 sentence type, a talker ID followed by
+// a formatter.
+type SentenceType string
+
+// Talker returns the talker ID of the sentence type. The talker ID of
+// proprietary sentences is "P".
+func (t SentenceType) Talker() string {
+	talker, _ := SplitType(string(t))
+	return talker
+}
+
+// Formatter returns the sentence formatter of the sentence type. The
+// formatter of proprietary sentences includes the manufacturer code.
+func (t SentenceType) Formatter() string {
+	_, formatter := SplitType(string(t))
+	return formatter
+}
+
+// anyTalker is the talker ID placeholder matching any talker.
+const anyTalker = "--"
+
+// hasTalker returns whether typ begins with a valid two character
+// talker ID. Proprietary sentences do not have a talker ID.
+func hasTalker(typ string) bool {
+	if len(typ) < 3 || typ[0] == 'P' {
+		return false
+	}
+	for _, c := range []byte(typ[:2]) {
+		if (c < 'A' || 'Z' < c) && (c < '0' || '9' < c) {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitType returns the talker ID and formatter of the sentence type typ,
+// for example the Type field of a parsed struct.
+// Proprietary sentences have the talker ID "P".
+func SplitType(typ string) (talker, formatter string) {
+	switch {
+	case strings.HasPrefix(typ, "P"):
+		return typ[:1], typ[1:]
+	case len(typ) < 3:
+		return "", typ
+	default:
+		return typ[:2], typ[2:]
+	}
+}
+
+// Type returns the sentence type, the talker ID followed by the formatter.
+func (s Sentence) Type() SentenceType {
+	return SentenceType(s.Talker + s.Formatter)
+}
+
+// String returns the NMEA 0183 encoding of the sentence. If the sentence
+// has a checksum, it is calculated from the sentence's current contents.
+// The returned sentence does not include a line terminator.
+func (s Sentence) String() string {
+	var b strings.Builder
+	b.WriteByte(s.Start)
+	b.WriteString(s.Talker)
+	b.WriteString(s.Formatter)
+	for _, f := range s.Fields {
+		b.WriteByte(',')
+		b.WriteString(f)
+	}
+	if s.HasChecksum {
+		sum := checksum(b.String()[1:])
+		b.WriteByte('*')
+		b.WriteByte(hexDigits[sum>>4])
+		b.WriteByte(hexDigits[sum&0xf])
+	}
+	return b.String()
+}
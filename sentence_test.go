@@ -0,0 +1,111 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var sentenceTests = []struct {
+	sentence string
+	want     Sentence
+	err      error
+}{
+	{
+		sentence: "$GPGGA,123519,4807.038,N,01131.000,W,1,2,3,4,M,5,M,,*41",
+		want: Sentence{
+			Start: '$', Talker: "GP", Formatter: "GGA",
+			Fields:      []string{"123519", "4807.038", "N", "01131.000", "W", "1", "2", "3", "4", "M", "5", "M", "", ""},
+			HasChecksum: true, Checksum: 0x41, ChecksumValid: true,
+		},
+	},
+	{
+		sentence: "!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26",
+		want: Sentence{
+			Start: '!', Talker: "AI", Formatter: "VDM",
+			Fields:      []string{"1", "1", "", "A", "13aEOK?P00PD2wVMdLDRhgvL289?", "0"},
+			HasChecksum: true, Checksum: 0x26, ChecksumValid: true,
+		},
+	},
+	{
+		sentence: "$PGRME,15.0,M,45.0,M,25.0,M",
+		want: Sentence{
+			Start: '$', Talker: "P", Formatter: "GRME",
+			Fields: []string{"15.0", "M", "45.0", "M", "25.0", "M"},
+		},
+	},
+	{
+		sentence: "$IIXDR,C,19.5,C,AIR*00",
+		want: Sentence{
+			Start: '$', Talker: "II", Formatter: "XDR",
+			Fields:      []string{"C", "19.5", "C", "AIR"},
+			HasChecksum: true, Checksum: 0x00,
+		},
+		err: ErrChecksum,
+	},
+}
+
+func TestParseSentence(t *testing.T) {
+	for _, test := range sentenceTests {
+		for _, parse := range []struct {
+			name string
+			fn   func(string) (Sentence, error)
+		}{
+			{name: "ParseSentence", fn: ParseSentence},
+			{name: "ParseSentenceBytes", fn: func(s string) (Sentence, error) {
+				b := []byte(s)
+				got, err := ParseSentenceBytes(b)
+				// The result must not refer to the input buffer.
+				for i := range b {
+					b[i] = 'x'
+				}
+				return got, err
+			}},
+		} {
+			got, err := parse.fn(test.sentence)
+			if !errors.Is(err, test.err) {
+				t.Errorf("%s: unexpected error for %q: got:%v want:%v", parse.name, test.sentence, err, test.err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%s: unexpected result for %q:\ngot: %#v\nwant:%#v", parse.name, test.sentence, got, test.want)
+			}
+			if test.err == nil && got.String() != test.sentence {
+				t.Errorf("%s: unexpected round trip: got:%q want:%q", parse.name, got.String(), test.sentence)
+			}
+		}
+	}
+}
+
+func TestRegistryFallback(t *testing.T) {
+	const sentence = "$IIXDR,C,19.5,C,AIR*07"
+	r := NewDefaultRegistry()
+	_, err := r.Parse(sentence)
+	if err != ErrNotRegistered {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrNotRegistered)
+	}
+
+	r.SetFallback(true)
+	got, err := r.Parse(sentence)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s, ok := got.(Sentence)
+	if !ok {
+		t.Fatalf("unexpected result type: %T", got)
+	}
+	if s.Type() != "IIXDR" || !s.ChecksumValid {
+		t.Errorf("unexpected result: %#v", s)
+	}
+	if s.String() != sentence {
+		t.Errorf("unexpected round trip: got:%q want:%q", s.String(), sentence)
+	}
+
+	got, err = r.Parse("$GPHDT,274.07,T*03")
+	if _, ok := got.(HDT); !ok || err != nil {
+		t.Errorf("unexpected result for registered type: %T %v", got, err)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TagBlock is a NMEA 4.10 tag block. Tag blocks precede a sentence and
+// are delimited by backslashes, for example
+//  \s:r3669961,c:1697040000*5C\$GPGGA,...
+// Fields with zero values are not present in the tag block.
+type TagBlock struct {
+	Source      string // Source is the source identifier, s.
+	Destination string // Destination is the destination identifier, d.
+
+	// Time is the UNIX time of the sentence, c. Values with more
+	// than ten digits are interpreted as milliseconds, and times
+	// with fractional seconds are written as milliseconds.
+	Time time.Time
+
+	RelativeTime int64    // RelativeTime is the relative time, r.
+	LineCount    int      // LineCount is the line count, n.
+	Group        TagGroup // Group is the sentence grouping, g.
+	Text         string   // Text is a text string, t.
+}
+
+// TagGroup is the grouping of a sentence that is part of a group of
+// related sentences.
+type TagGroup struct {
+	Sentence int // Sentence is the number of the sentence in the group.
+	Total    int // Total is the number of sentences in the group.
+	ID       int // ID is the group identifier.
+}
+
+// ParseTagBlock parses the tag blocks at the start of s and returns the
+// tag block parameters and the remainder of s following the tag blocks.
+// If s holds more than one consecutive tag block, their parameters are
+// combined. If s does not start with a tag block, the zero TagBlock and
+// s are returned. The checksum of each tag block is checked.
+func ParseTagBlock(s string) (tb TagBlock, sentence string, err error) {
+	return parseTagBlock(s, ChecksumReport)
+}
+
+// parseTagBlock implements ParseTagBlock, checking the checksum of each
+// tag block unless policy is ChecksumIgnore.
+func parseTagBlock(s string, policy ChecksumPolicy) (tb TagBlock, sentence string, err error) {
+	for len(s) != 0 && s[0] == '\\' {
+		var block string
+		block, s, err = nextTagBlock(s, policy)
+		if err != nil {
+			return TagBlock{}, s, err
+		}
+		for _, param := range strings.Split(block, ",") {
+			err = tb.set(param)
+			if err != nil {
+				return TagBlock{}, s, err
+			}
+		}
+	}
+	return tb, s, nil
+}
+
+// skipTagBlocks returns s after any leading tag blocks, checking their
+// framing and, unless policy is ChecksumIgnore, their checksums. It does
+// not retain s in returned errors.
+func skipTagBlocks(s string, policy ChecksumPolicy) (string, error) {
+	for len(s) != 0 && s[0] == '\\' {
+		var err error
+		_, s, err = nextTagBlock(s, policy)
+		if err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// nextTagBlock returns the parameters of the tag block at the start of s,
+// without its checksum, and the text following the tag block. The checksum
+// is not compared if policy is ChecksumIgnore.
+func nextTagBlock(s string, policy ChecksumPolicy) (block, rest string, err error) {
+	end := strings.IndexByte(s[1:], '\\')
+	if end < 0 {
+		return "", s, ErrTagBlock
+	}
+	block, rest = s[1:end+1], s[end+2:]
+	star := strings.LastIndexByte(block, '*')
+	if star < 0 {
+		return "", rest, ErrTagBlock
+	}
+	want, ok := parseHexByte(block[star+1:], true)
+	if !ok {
+		return "", rest, ErrTagBlock
+	}
+	block = block[:star]
+	if sum := byte(checksum(block)); sum != want && policy != ChecksumIgnore {
+		return "", rest, &ChecksumError{Computed: sum, Expected: want}
+	}
+	return block, rest, nil
+}
+
+// parseHexByte returns the value of the two digit hexadecimal number s.
+// Lowercase digits are only accepted if lower is true.
+func parseHexByte(s string, lower bool) (byte, bool) {
+	if len(s) != 2 {
+		return 0, false
+	}
+	var v byte
+	for i := 0; i < 2; i++ {
+		c := s[i]
+		switch {
+		case '0' <= c && c <= '9':
+			c -= '0'
+		case 'A' <= c && c <= 'F':
+			c -= 'A' - 10
+		case lower && 'a' <= c && c <= 'f':
+			c -= 'a' - 10
+		default:
+			return 0, false
+		}
+		v = v<<4 | c
+	}
+	return v, true
+}
+
+// set sets the tag block parameter described by param. Unknown
+// parameters are ignored.
+func (tb *TagBlock) set(param string) error {
+	colon := strings.IndexByte(param, ':')
+	if colon < 0 {
+		return ErrTagBlock
+	}
+	code, val := param[:colon], param[colon+1:]
+	var err error
+	switch code {
+	case "s":
+		tb.Source = val
+	case "d":
+		tb.Destination = val
+	case "c":
+		var t int64
+		t, err = strconv.ParseInt(val, 10, 64)
+		if len(val) > 10 {
+			tb.Time = time.Unix(t/1e3, t%1e3*1e6).UTC()
+		} else {
+			tb.Time = time.Unix(t, 0).UTC()
+		}
+	case "r":
+		tb.RelativeTime, err = strconv.ParseInt(val, 10, 64)
+	case "n":
+		tb.LineCount, err = strconv.Atoi(val)
+	case "g":
+		parts := strings.Split(val, "-")
+		if len(parts) != 3 {
+			return ErrTagBlock
+		}
+		var g [3]int
+		for i, p := range parts {
+			g[i], err = strconv.Atoi(p)
+			if err != nil {
+				return ErrTagBlock
+			}
+		}
+		tb.Group = TagGroup{Sentence: g[0], Total: g[1], ID: g[2]}
+	case "t":
+		tb.Text = val
+	}
+	if err != nil {
+		return ErrTagBlock
+	}
+	return nil
+}
+
+// AppendTagBlock appends the NMEA 4.10 tag block encoding of tb, including
+// its checksum, to dst and returns the extended buffer. Nothing is appended
+// if tb is the zero TagBlock. A sentence may be appended to the returned
+// buffer using AppendSentence.
+func AppendTagBlock(dst []byte, tb TagBlock) ([]byte, error) {
+	if tb == (TagBlock{}) {
+		return dst, nil
+	}
+	for _, s := range []string{tb.Source, tb.Destination, tb.Text} {
+		if strings.ContainsAny(s, reserved) {
+			return dst, ErrReserved
+		}
+	}
+
+	start := len(dst)
+	dst = append(dst, '\\')
+	param := func(code byte) {
+		if len(dst) > start+1 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, code, ':')
+	}
+	if tb.Source != "" {
+		param('s')
+		dst = append(dst, tb.Source...)
+	}
+	if tb.Destination != "" {
+		param('d')
+		dst = append(dst, tb.Destination...)
+	}
+	if !tb.Time.IsZero() {
+		param('c')
+		if tb.Time.Nanosecond() == 0 {
+			dst = strconv.AppendInt(dst, tb.Time.Unix(), 10)
+		} else {
+			dst = strconv.AppendInt(dst, tb.Time.UnixNano()/1e6, 10)
+		}
+	}
+	if tb.RelativeTime != 0 {
+		param('r')
+		dst = strconv.AppendInt(dst, tb.RelativeTime, 10)
+	}
+	if tb.LineCount != 0 {
+		param('n')
+		dst = strconv.AppendInt(dst, int64(tb.LineCount), 10)
+	}
+	if tb.Group != (TagGroup{}) {
+		param('g')
+		dst = strconv.AppendInt(dst, int64(tb.Group.Sentence), 10)
+		dst = append(dst, '-')
+		dst = strconv.AppendInt(dst, int64(tb.Group.Total), 10)
+		dst = append(dst, '-')
+		dst = strconv.AppendInt(dst, int64(tb.Group.ID), 10)
+	}
+	if tb.Text != "" {
+		param('t')
+		dst = append(dst, tb.Text...)
+	}
+	sum := checksum(string(dst[start+1:]))
+	return append(dst, '*', hexDigits[sum>>4], hexDigits[sum&0xf], '\\'), nil
+}
+
+// String returns the NMEA 4.10 encoding of the tag block. Tag blocks
+// holding reserved characters are returned as the empty string.
+func (tb TagBlock) String() string {
+	b, err := AppendTagBlock(nil, tb)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,193 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var tagBlockTests = []struct {
+	line     string
+	want     TagBlock
+	sentence string
+	err      error
+}{
+	{
+		line:     `\s:r3669961,c:1697040000*77\$GPHDT,274.07,T*03`,
+		want:     TagBlock{Source: "r3669961", Time: time.Unix(1697040000, 0).UTC()},
+		sentence: "$GPHDT,274.07,T*03",
+	},
+	{
+		line: `\g:1-2-73874,n:157036,s:r003669945,c:1241544035*4a\!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26`,
+		want: TagBlock{
+			Source:    "r003669945",
+			Time:      time.Unix(1241544035, 0).UTC(),
+			LineCount: 157036,
+			Group:     TagGroup{Sentence: 1, Total: 2, ID: 73874},
+		},
+		sentence: "!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26",
+	},
+	{
+		line: `\s:src,d:dst,c:1697040000123,r:42,n:7,g:1-3-12,t:hello world*6D\$GPHDT,274.07,T*03`,
+		want: TagBlock{
+			Source:       "src",
+			Destination:  "dst",
+			Time:         time.Unix(1697040000, 123e6).UTC(),
+			RelativeTime: 42,
+			LineCount:    7,
+			Group:        TagGroup{Sentence: 1, Total: 3, ID: 12},
+			Text:         "hello world",
+		},
+		sentence: "$GPHDT,274.07,T*03",
+	},
+	{
+		line:     "$GPHDT,274.07,T*03",
+		sentence: "$GPHDT,274.07,T*03",
+	},
+	{
+		line:     `\s:SRC1*3A\\n:7*63\$GPHDT,274.07,T*03`,
+		want:     TagBlock{Source: "SRC1", LineCount: 7},
+		sentence: "$GPHDT,274.07,T*03",
+	},
+	{line: `\s:r3669961,c:1697040000*78\$GPHDT,274.07,T*03`, err: ErrChecksum},
+	{line: `\s:r3669961,c:1697040000\$GPHDT,274.07,T*03`, err: ErrTagBlock},
+	{line: `\s:r3669961,c:1697040000*77$GPHDT,274.07,T*03`, err: ErrTagBlock},
+}
+
+func TestParseTagBlock(t *testing.T) {
+	for _, test := range tagBlockTests {
+		got, sentence, err := ParseTagBlock(test.line)
+		if !errors.Is(err, test.err) {
+			t.Errorf("unexpected error for %q: got:%v want:%v", test.line, err, test.err)
+		}
+		if err != nil {
+			continue
+		}
+		if got != test.want {
+			t.Errorf("unexpected tag block for %q:\ngot: %#v\nwant:%#v", test.line, got, test.want)
+		}
+		if sentence != test.sentence {
+			t.Errorf("unexpected sentence for %q: got:%q want:%q", test.line, sentence, test.sentence)
+		}
+
+		b, err := AppendTagBlock(nil, got)
+		if err != nil {
+			t.Errorf("unexpected error appending tag block: %v", err)
+		}
+		b = append(b, sentence...)
+		rt, _, err := ParseTagBlock(string(b))
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %v", b, err)
+		}
+		if rt != got {
+			t.Errorf("unexpected round trip tag block via %q:\ngot: %#v\nwant:%#v", b, rt, got)
+		}
+
+		// Tag blocks are accepted by the parsing functions.
+		if _, err := Parse(test.line); err != nil {
+			t.Errorf("unexpected error parsing %q: %v", test.line, err)
+		}
+		if _, err := ParseBytes([]byte(test.line)); err != nil {
+			t.Errorf("unexpected error parsing %q: %v", test.line, err)
+		}
+		if _, err := ParseSentence(test.line); err != nil {
+			t.Errorf("unexpected error parsing %q: %v", test.line, err)
+		}
+	}
+
+	var hdt HDT
+	err := ParseTo(&hdt, `\s:r3669961,c:1697040000*78\$GPHDT,274.07,T*03`)
+	if !errors.Is(err, ErrChecksum) {
+		t.Errorf("unexpected error for invalid tag block checksum: got:%v want:%v", err, ErrChecksum)
+	}
+
+	_, err = AppendTagBlock(nil, TagBlock{Text: "a,b"})
+	if err != ErrReserved {
+		t.Errorf("unexpected error for reserved character: got:%v want:%v", err, ErrReserved)
+	}
+}
+
+func TestParseWithTagBlock(t *testing.T) {
+	for _, test := range tagBlockTests {
+		tb, got, err := ParseWithTagBlock(test.line)
+		if !errors.Is(err, test.err) {
+			t.Errorf("unexpected error for %q: got:%v want:%v", test.line, err, test.err)
+		}
+		if err != nil {
+			continue
+		}
+		if tb != test.want {
+			t.Errorf("unexpected tag block for %q:\ngot: %#v\nwant:%#v", test.line, tb, test.want)
+		}
+		want, err := Parse(test.sentence)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", test.sentence, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected value for %q:\ngot: %#v\nwant:%#v", test.line, got, want)
+		}
+	}
+
+	r := NewDefaultRegistry()
+	r.SetParseOptions(ParseOptions{Checksum: ChecksumIgnore})
+	tb, got, err := r.ParseWithTagBlock(`\s:a*00\$GPHDT,1,T*2A`)
+	if err != nil {
+		t.Errorf("unexpected error ignoring checksums: %v", err)
+	}
+	if tb != (TagBlock{Source: "a"}) || got != (HDT{Type: "GPHDT", Heading: 1, Checksum: 0x2a}) {
+		t.Errorf("unexpected result ignoring checksums: got:%#v %#v", tb, got)
+	}
+}
+
+func TestDecoderTagBlock(t *testing.T) {
+	var lines []string
+	for _, test := range tagBlockTests {
+		if test.err == nil {
+			lines = append(lines, test.line)
+		}
+	}
+	d := NewDecoder(strings.NewReader(strings.Join(lines, "\r\n")))
+	for i, line := range lines {
+		_, err := d.Decode()
+		if err != nil {
+			t.Errorf("unexpected error decoding %q: %v", line, err)
+		}
+		if d.TagBlock() != tagBlockTests[i].want {
+			t.Errorf("unexpected tag block for %q:\ngot: %#v\nwant:%#v", line, d.TagBlock(), tagBlockTests[i].want)
+		}
+		if d.Sentence() != tagBlockTests[i].sentence {
+			t.Errorf("unexpected sentence for %q: got:%q want:%q", line, d.Sentence(), tagBlockTests[i].sentence)
+		}
+	}
+}
+
+func TestDecoderTagBlockChecksumIgnore(t *testing.T) {
+	const line = `\s:a*00\$GPHDT,1,T*2A`
+	r := NewDefaultRegistry()
+	r.SetParseOptions(ParseOptions{Checksum: ChecksumIgnore})
+	d := NewDecoder(strings.NewReader(line))
+	d.Registry = r
+	got, err := d.Decode()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := HDT{Type: "GPHDT", Heading: 1, Checksum: 0x2a}
+	if got != want {
+		t.Errorf("unexpected result: got:%#v want:%#v", got, want)
+	}
+	if d.TagBlock() != (TagBlock{Source: "a"}) {
+		t.Errorf("unexpected tag block: got:%#v want:%#v", d.TagBlock(), TagBlock{Source: "a"})
+	}
+
+	d = NewDecoder(strings.NewReader(line))
+	_, err = d.Decode()
+	if !errors.Is(err, ErrChecksum) {
+		t.Errorf("unexpected error without ignoring checksums: got:%v want:%v", err, ErrChecksum)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// TimeOfDay is a UTC time of day expressed as the duration since midnight.
+// It may be used in place of time.Time for fields parsed with the "time"
+// method.
+type TimeOfDay time.Duration
+
+// On returns the time of day on the UTC date of d.
+func (t TimeOfDay) On(d time.Time) time.Time {
+	y, m, day := d.In(time.UTC).Date()
+	return time.Date(y, m, day, 0, 0, 0, 0, time.UTC).Add(time.Duration(t))
+}
+
+// String returns the time of day in the form hh:mm:ss.sss, with the
+// fractional seconds omitted if they are zero.
+func (t TimeOfDay) String() string {
+	return t.On(time.Time{}).Format("15:04:05.999999999")
+}
+
+// timeOfDay returns the time of day of t in UTC.
+func timeOfDay(t time.Time) TimeOfDay {
+	t = t.In(time.UTC)
+	h, m, s := t.Clock()
+	return TimeOfDay(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(s)*time.Second + time.Duration(t.Nanosecond()))
+}
+
+// Timestamp returns the time of the RMC fix, combining its Time and Date.
+// It returns the zero time if Date is zero.
+func (m RMC) Timestamp() time.Time {
+	if m.Date.IsZero() {
+		return time.Time{}
+	}
+	return timeOfDay(m.Time).On(m.Date)
+}
+
+// Timestamp returns the UTC time of the ZDA sentence, combining its Time,
+// Day, Month and Year. It returns the zero time if Year is zero.
+func (m ZDA) Timestamp() time.Time {
+	if m.Year == 0 {
+		return time.Time{}
+	}
+	return timeOfDay(m.Time).On(time.Date(m.Year, time.Month(m.Month), int(m.Day), 0, 0, 0, 0, time.UTC))
+}
+
+var timeOfDayType = reflect.TypeOf(TimeOfDay(0))
+
+// parseTimeOfDay parses an NMEA hhmmss[.s...] time of day. Any number
+// of fractional second digits is accepted, but a decimal point must be
+// followed by at least one digit. Digits beyond nanosecond
+// precision are truncated.
+func parseTimeOfDay(field string) (TimeOfDay, error) {
+	if len(field) < 6 {
+		return 0, ErrTime
+	}
+	var clock [3]int
+	for i := range clock {
+		d0, d1 := field[2*i], field[2*i+1]
+		if !isDigit(d0) || !isDigit(d1) {
+			return 0, ErrTime
+		}
+		clock[i] = int(d0-'0')*10 + int(d1-'0')
+	}
+	h, m, s := clock[0], clock[1], clock[2]
+	if h > 23 || m > 59 || s > 59 {
+		return 0, ErrTime
+	}
+	t := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+
+	frac := field[6:]
+	if frac == "" {
+		return TimeOfDay(t), nil
+	}
+	if frac[0] != '.' || len(frac) == 1 {
+		return 0, ErrTime
+	}
+	scale := time.Second
+	for i := 1; i < len(frac); i++ {
+		if !isDigit(frac[i]) {
+			return 0, ErrTime
+		}
+		scale /= 10
+		t += time.Duration(frac[i]-'0') * scale
+	}
+	return TimeOfDay(t), nil
+}
+
+func isDigit(b byte) bool { return '0' <= b && b <= '9' }
+
+// appendTimeOfDay appends the NMEA hhmmss[.s...] representation of t,
+// with trailing zeros of the fractional seconds removed.
+func appendTimeOfDay(dst []byte, t TimeOfDay) ([]byte, error) {
+	if t < 0 || t >= TimeOfDay(24*time.Hour) {
+		return dst, ErrTime
+	}
+	d := time.Duration(t)
+	for _, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		v := int(d / unit)
+		d -= time.Duration(v) * unit
+		dst = append(dst, byte('0'+v/10), byte('0'+v%10))
+	}
+	if d == 0 {
+		return dst, nil
+	}
+	var buf [10]byte
+	frac := strconv.AppendInt(buf[:0], int64(d)+1e9, 10)[1:]
+	for frac[len(frac)-1] == '0' {
+		frac = frac[:len(frac)-1]
+	}
+	dst = append(dst, '.')
+	return append(dst, frac...), nil
+}
@@ -0,0 +1,107 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var timeOfDayTests = []struct {
+	field string
+	want  TimeOfDay
+	err   error
+}{
+	{field: "123519", want: TimeOfDay(12*time.Hour + 35*time.Minute + 19*time.Second)},
+	{field: "123519.00", want: TimeOfDay(12*time.Hour + 35*time.Minute + 19*time.Second)},
+	{field: "123519.250", want: TimeOfDay(12*time.Hour + 35*time.Minute + 19*time.Second + 250*time.Millisecond)},
+	{field: "000000.05", want: TimeOfDay(50 * time.Millisecond)},
+	{field: "235959.1234567891", want: TimeOfDay(23*time.Hour + 59*time.Minute + 59*time.Second + 123456789)},
+	{field: "12351", err: ErrTime},
+	{field: "240000", err: ErrTime},
+	{field: "126000", err: ErrTime},
+	{field: "12a519", err: ErrTime},
+	{field: "123519,25", err: ErrTime},
+	{field: "123519.2x", err: ErrTime},
+	{field: "225444.", err: ErrTime},
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	for _, test := range timeOfDayTests {
+		got, err := parseTimeOfDay(test.field)
+		if !errors.Is(err, test.err) {
+			t.Errorf("unexpected error for %q: got:%v want:%v", test.field, err, test.err)
+		}
+		if got != test.want {
+			t.Errorf("unexpected result for %q: got:%v want:%v", test.field, got, test.want)
+		}
+	}
+}
+
+type timeTest struct {
+	Type string `nmea:"PXTIM"`
+
+	Time      time.Time `nmea:"time"`
+	TimeOfDay TimeOfDay `nmea:"time"`
+}
+
+func TestTimeFields(t *testing.T) {
+	const sentence = "$PXTIM,123519.25,000001.125*65"
+	var got timeTest
+	err := ParseTo(&got, sentence)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := timeTest{
+		Type:      "PXTIM",
+		Time:      time.Date(0, time.January, 1, 12, 35, 19, 250e6, time.UTC),
+		TimeOfDay: TimeOfDay(time.Second + 125*time.Millisecond),
+	}
+	if !got.Time.Equal(want.Time) || got.TimeOfDay != want.TimeOfDay {
+		t.Errorf("unexpected result:\ngot: %#v\nwant:%#v", got, want)
+	}
+	if s := got.TimeOfDay.String(); s != "00:00:01.125" {
+		t.Errorf("unexpected string: got:%q want:%q", s, "00:00:01.125")
+	}
+
+	s, err := Marshal(got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s != sentence {
+		t.Errorf("unexpected marshal result: got:%q want:%q", s, sentence)
+	}
+
+	_, err = Marshal(timeTest{TimeOfDay: TimeOfDay(25 * time.Hour)})
+	if !errors.Is(err, ErrTime) {
+		t.Errorf("unexpected error for out of range time of day: got:%v want:%v", err, ErrTime)
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	want := time.Date(1994, time.March, 23, 12, 35, 19, 500e6, time.UTC)
+
+	rmc := RMC{
+		Time: time.Date(0, time.January, 1, 12, 35, 19, 500e6, time.UTC),
+		Date: time.Date(1994, time.March, 23, 0, 0, 0, 0, time.UTC),
+	}
+	if got := rmc.Timestamp(); !got.Equal(want) {
+		t.Errorf("unexpected RMC timestamp: got:%v want:%v", got, want)
+	}
+	if got := (RMC{Time: rmc.Time}).Timestamp(); !got.IsZero() {
+		t.Errorf("unexpected RMC timestamp without date: got:%v want zero time", got)
+	}
+
+	zda := ZDA{Time: rmc.Time, Day: 23, Month: 3, Year: 1994}
+	if got := zda.Timestamp(); !got.Equal(want) {
+		t.Errorf("unexpected ZDA timestamp: got:%v want:%v", got, want)
+	}
+
+	tod := TimeOfDay(12*time.Hour + 35*time.Minute + 19*time.Second + 500*time.Millisecond)
+	if got := tod.On(rmc.Date); !got.Equal(want) {
+		t.Errorf("unexpected combined time: got:%v want:%v", got, want)
+	}
+}
@@ -8,21 +8,21 @@ import "time"
 
 // http://aprs.gids.nl/nmea/#bod
 type BOD struct {
-	Type string `nmea:"/G[LNP]BOD/"`
+	Type string `nmea:"--BOD"`
 
 	True        float64 `nmea:"number"`
-	_           [0]byte
+	_           [0]byte `nmea:"const=T"`
 	Magnetic    float64 `nmea:"number"`
-	_           [0]byte
-	Destination string `nmea:"string"`
-	Start       string `nmea:"string"`
+	_           [0]byte `nmea:"const=M"`
+	Destination string  `nmea:"string"`
+	Start       string  `nmea:"string"`
 
 	Checksum byte `nmea:"checksum"`
 }
 
 // http://aprs.gids.nl/nmea/#bwc
 type BWC struct {
-	Type string `nmea:"/G[LNP]BWC/"`
+	Type string `nmea:"--BWC"`
 
 	Timestamp  time.Time `nmea:"time"`
 	Latitude   float64   `nmea:"latlon"`
@@ -30,32 +30,34 @@ type BWC struct {
 	Longitude  float64   `nmea:"latlon"`
 	EastWest   string    `nmea:"string"`
 	True       float64   `nmea:"number"`
-	_          [0]byte
-	Magnetic   float64 `nmea:"number"`
-	_          [0]byte
-	Range      float64 `nmea:"number"`
-	RangeUnit  string  `nmea:"string"`
-	Waypoint   string  `nmea:"string"`
+	_          [0]byte   `nmea:"const=T"`
+	Magnetic   float64   `nmea:"number"`
+	_          [0]byte   `nmea:"const=M"`
+	Range      float64   `nmea:"number"`
+	RangeUnit  string    `nmea:"const=N"`
+	Waypoint   string    `nmea:"string"`
 
 	Checksum byte `nmea:"checksum"`
 }
 
 // http://aprs.gids.nl/nmea/#gll
 type GLL struct {
-	Type string `nmea:"/G[LNP]GLL/"`
+	Type string `nmea:"--GLL"`
 
 	Latitude   float64   `nmea:"latlon"`
 	NorthSouth string    `nmea:"string"`
 	Longitude  float64   `nmea:"latlon"`
 	EastWest   string    `nmea:"string"`
 	Timestamp  time.Time `nmea:"time"`
+	Status     Status    `nmea:"string"`
+	Mode       FAAMode   `nmea:"string"`
 
 	Checksum byte `nmea:"checksum"`
 }
 
 // http://aprs.gids.nl/nmea/#gga
 type GGA struct {
-	Type string `nmea:"/G[LNP]GGA/"`
+	Type string `nmea:"--GGA"`
 
 	Timestamp  time.Time `nmea:"time"`
 	Latitude   float64   `nmea:"latlon"`
@@ -63,16 +65,16 @@ type GGA struct {
 	Longitude  float64   `nmea:"latlon"`
 	EastWest   string    `nmea:"string"`
 
-	Quality    int `nmea:"number"`
-	Satellites int `nmea:"number"`
+	Quality    FixQuality `nmea:"number"`
+	Satellites int        `nmea:"number"`
 
 	HDOP float64 `nmea:"number"`
 
 	Altitude     float64 `nmea:"number"`
-	AltitudeUnit string  `nmea:"string"`
+	AltitudeUnit string  `nmea:"const=M"`
 
 	Separation     float64 `nmea:"number"`
-	SeparationUnit string  `nmea:"string"`
+	SeparationUnit string  `nmea:"const=M"`
 
 	Age float64 `nmea:"number"`
 
@@ -83,23 +85,14 @@ type GGA struct {
 
 // http://aprs.gids.nl/nmea/#gsa
 type GSA struct {
-	Type string `nmea:"/G[LNP]GSA/"`
-
-	Mode string `nmea:"string"`
-	Fix  int    `nmea:"number"`
-
-	SV0  string `nmea:"string"`
-	SV1  string `nmea:"string"`
-	SV2  string `nmea:"string"`
-	SV3  string `nmea:"string"`
-	SV4  string `nmea:"string"`
-	SV5  string `nmea:"string"`
-	SV6  string `nmea:"string"`
-	SV7  string `nmea:"string"`
-	SV8  string `nmea:"string"`
-	SV9  string `nmea:"string"`
-	SV10 string `nmea:"string"`
-	SV11 string `nmea:"string"`
+	Type string `nmea:"--GSA"`
+
+	Mode SelectionMode `nmea:"string"`
+	Fix  FixType       `nmea:"number"`
+
+	// SVs holds the IDs of the satellites
+	// used in the solution.
+	SVs [12]string `nmea:"string"`
 
 	PDOP float64 `nmea:"number"`
 	HDOP float64 `nmea:"number"`
@@ -110,81 +103,57 @@ type GSA struct {
 
 // http://aprs.gids.nl/nmea/#gsv
 type GSV struct {
-	Type string `nmea:"/G[LNP]GSV/"`
+	Type string `nmea:"--GSV"`
 
 	Messages      int `nmea:"number"`
 	MessageNumber int `nmea:"number"`
 
 	SatellitesInView int `nmea:"number"`
 
-	Satellite0PRN int `nmea:"number"`
-	Elevation0    int `nmea:"number"`
-	Azimuth0      int `nmea:"number"`
-	SNR0          int `nmea:"number"`
-
-	Satellite1PRN int `nmea:"number"`
-	Elevation1    int `nmea:"number"`
-	Azimuth1      int `nmea:"number"`
-	SNR1          int `nmea:"number"`
-
-	Satellite2PRN int `nmea:"number"`
-	Elevation2    int `nmea:"number"`
-	Azimuth2      int `nmea:"number"`
-	SNR2          int `nmea:"number"`
-
-	Satellite3PRN int `nmea:"number"`
-	Elevation3    int `nmea:"number"`
-	Azimuth3      int `nmea:"number"`
-	SNR3          int `nmea:"number"`
+	Satellites []GSVSatellite `nmea:"repeat=4"`
 
 	Checksum byte `nmea:"checksum"`
 }
 
+// GSVSatellite is the satellite information held in a GSV sentence.
+type GSVSatellite struct {
+	PRN       int `nmea:"number"`
+	Elevation int `nmea:"number"`
+	Azimuth   int `nmea:"number"`
+	SNR       int `nmea:"number"`
+}
+
 // http://aprs.gids.nl/nmea/#hdt
 type HDT struct {
-	Type string `nmea:"/G[LNP]HDT/"`
+	Type string `nmea:"--HDT"`
 
 	Heading float64 `nmea:"number"`
-	_       [0]byte
+	_       [0]byte `nmea:"const=T"`
 
 	Checksum byte `nmea:"checksum"`
 }
 
 // http://aprs.gids.nl/nmea/#r00
 type R00 struct {
-	Type string `nmea:"/G[LNP]R00/"`
-
-	WP0  string `nmea:"string"`
-	WP1  string `nmea:"string"`
-	WP2  string `nmea:"string"`
-	WP3  string `nmea:"string"`
-	WP4  string `nmea:"string"`
-	WP5  string `nmea:"string"`
-	WP6  string `nmea:"string"`
-	WP7  string `nmea:"string"`
-	WP8  string `nmea:"string"`
-	WP9  string `nmea:"string"`
-	WP10 string `nmea:"string"`
-	WP11 string `nmea:"string"`
-	WP13 string `nmea:"string"`
+	Type string `nmea:"--R00"`
+
+	Waypoints []string `nmea:"string,repeat=14"`
 
 	Checksum byte `nmea:"checksum"`
 }
 
 // http://aprs.gids.nl/nmea/#rma
 type RMA struct {
-	Type string `nmea:"/G[LNP]RMA/"`
+	Type string `nmea:"--RMA"`
 
-	Status string `nmea:"string"`
+	Status Status `nmea:"string"`
 
 	Latitude   float64 `nmea:"latlon"`
 	NorthSouth string  `nmea:"string"`
 	Longitude  float64 `nmea:"latlon"`
 	EastWest   string  `nmea:"string"`
 
-	_, _ [0]byte
-
-	Speed            float64 `nmea:"number"`
+	Speed            float64 `nmea:"number,@8"`
 	CourseOverGround int     `nmea:"number"`
 	Variation        float64 `nmea:"number"`
 	VarDirection     string  `nmea:"string"`
@@ -194,9 +163,9 @@ type RMA struct {
 
 // http://aprs.gids.nl/nmea/#rmb
 type RMB struct {
-	Type string `nmea:"/G[LNP]RMB/"`
+	Type string `nmea:"--RMB"`
 
-	Status string `nmea:"string"`
+	Status Status `nmea:"string"`
 
 	CrosstrackError  float64 `nmea:"number"`
 	CorrectDirection string  `nmea:"string"`
@@ -213,18 +182,18 @@ type RMB struct {
 	BearingToDestination float64 `nmea:"number"`
 	ClosingVelocity      float64 `nmea:"number"`
 
-	ArrivalStatus string `nmea:"string"`
+	ArrivalStatus Status `nmea:"string"`
 
 	Checksum byte `nmea:"checksum"`
 }
 
 // http://aprs.gids.nl/nmea/#rmc
 type RMC struct {
-	Type string `nmea:"/G[LNP]RMC/"`
+	Type string `nmea:"--RMC"`
 
 	Time time.Time `nmea:"time"`
 
-	Status string `nmea:"string"`
+	Status Status `nmea:"string"`
 
 	Latitude   float64 `nmea:"latlon"`
 	NorthSouth string  `nmea:"string"`
@@ -243,23 +212,26 @@ type RMC struct {
 }
 
 // http://aprs.gids.nl/nmea/#rte
-// TODO(kortschak): $GPRTE requires multiple field handling.
-//
-// Routes
-//
-// eg. $GPRTE,2,1,c,0,PBRCPK,PBRTO,PTELGR,PPLAND,PYAMBU,PPFAIR,PWARRN,PMORTL,PLISMR*73
-//     $GPRTE,2,2,c,0,PCRESY,GRYRIE,GCORIO,GWERR,GWESTG,7FED*34
-//            1 2 3 4 5 ..
-//
-//     1. Number of sentences in sequence
-//     2. Sentence number
-//     3. 'c' = Current active route, 'w' = waypoint list starts with destination waypoint
-//     4. Name or number of the active route
-//     5. onwards, Names of waypoints in Route
+type RTE struct {
+	Type string `nmea:"--RTE"`
+
+	Messages      int `nmea:"number"`
+	MessageNumber int `nmea:"number"`
+
+	// Mode is "c" for the current active route and
+	// "w" for a waypoint list starting with the
+	// destination waypoint.
+	Mode  string `nmea:"string"`
+	Route string `nmea:"string"`
+
+	Waypoints []string `nmea:"rest"`
+
+	Checksum byte `nmea:"checksum"`
+}
 
 // http://aprs.gids.nl/nmea/#trf
 type TRF struct {
-	Type string `nmea:"/G[LNP]TRF/"`
+	Type string `nmea:"--TRF"`
 
 	Time time.Time `nmea:"time"`
 	Date time.Time `nmea:"date"`
@@ -279,43 +251,43 @@ type TRF struct {
 
 // http://aprs.gids.nl/nmea/#stn
 type STN struct {
-	Type string `nmea:"/G[LNP]STN/"`
+	Type string `nmea:"--STN"`
 
 	Talker byte `nmea:"number"`
 }
 
 // http://aprs.gids.nl/nmea/#vbw
 type VBW struct {
-	Type string `nmea:"/G[LNP]VBW/"`
+	Type string `nmea:"--VBW"`
 
 	LongitudinalWaterSpeed float64 `nmea:"number"`
 	TransverseWaterSpeed   float64 `nmea:"number"`
-	WaterSpeedStatus       string  `nmea:"string"`
+	WaterSpeedStatus       Status  `nmea:"string"`
 
 	LongitudinalGroundSpeed float64 `nmea:"number"`
 	TransverseGroundSpeed   float64 `nmea:"number"`
-	GroundSpeedStatus       string  `nmea:"string"`
+	GroundSpeedStatus       Status  `nmea:"string"`
 }
 
 // http://aprs.gids.nl/nmea/#vtg
 type VTG struct {
-	Type string `nmea:"/G[LNP]VTG/"`
+	Type string `nmea:"--VTG"`
 
 	TrackTrue     float64 `nmea:"number"`
-	_             [0]byte
+	_             [0]byte `nmea:"const=T"`
 	TrackMagnetic float64 `nmea:"number"`
-	_             [0]byte
+	_             [0]byte `nmea:"const=M"`
 	SpeedKnots    float64 `nmea:"number"`
-	_             [0]byte
+	_             [0]byte `nmea:"const=N"`
 	SpeedKph      float64 `nmea:"number"`
-	_             [0]byte
+	_             [0]byte `nmea:"const=K"`
 
 	Checksum byte `nmea:"checksum"`
 }
 
 // http://aprs.gids.nl/nmea/#wpl
 type WPL struct {
-	Type string `nmea:"/G[LNP]WPL/"`
+	Type string `nmea:"--WPL"`
 
 	Latitude   float64 `nmea:"latlon"`
 	NorthSouth string  `nmea:"string"`
@@ -329,10 +301,10 @@ type WPL struct {
 
 // http://aprs.gids.nl/nmea/#xte
 type XTE struct {
-	Type string `nmea:"/G[LNP]XTE/"`
+	Type string `nmea:"--XTE"`
 
-	GeneralWarning string `nmea:"string"`
-	LockFlag       string `nmea:"string"`
+	GeneralWarning Status `nmea:"string"`
+	LockFlag       Status `nmea:"string"`
 
 	CrossTrackError float64 `nmea:"number"`
 	Steer           string  `nmea:"string"`
@@ -343,7 +315,7 @@ type XTE struct {
 
 // http://aprs.gids.nl/nmea/#zda
 type ZDA struct {
-	Type string `nmea:"/G[LNP]ZDA/"`
+	Type string `nmea:"--ZDA"`
 
 	Time            time.Time `nmea:"time"`
 	Day             byte      `nmea:"number"`
@@ -358,11 +330,11 @@ type RME struct {
 	Type string `nmea:"PGRME"`
 
 	HPE   float64 `nmea:"number"`
-	_     [0]byte
+	_     [0]byte `nmea:"const=M"`
 	VPE   float64 `nmea:"number"`
-	_     [0]byte
+	_     [0]byte `nmea:"const=M"`
 	OSEPE float64 `nmea:"number"`
-	_     [0]byte
+	_     [0]byte `nmea:"const=M"`
 
 	Checksum byte `nmea:"checksum"`
 }
@@ -381,8 +353,8 @@ type RMZ struct {
 	Type string `nmea:"PGRMZ"`
 
 	Altitude              float64 `nmea:"number"`
-	_                     [0]byte
-	PositionFixDimensions int8 `nmea:"number"`
+	_                     [0]byte `nmea:"const=f"`
+	PositionFixDimensions int8    `nmea:"number"`
 
 	Checksum byte `nmea:"checksum"`
 }
@@ -400,7 +372,7 @@ type LIB struct {
 
 // https://www.trimble.com/oem_receiverhelp/v4.44/en/NMEA-0183messages_GNS.html
 type GNS struct {
-	Type string `nmea:"/G[LNP]GNS/"`
+	Type string `nmea:"--GNS"`
 
 	Timestamp  time.Time `nmea:"time"`
 	Latitude   float64   `nmea:"latlon"`
@@ -424,10 +396,10 @@ type GNS struct {
 
 // http://www.nuovamarea.net/pytheas_9.html
 type THS struct {
-	Type string `nmea:"/..THS/"`
+	Type string `nmea:"--THS"`
 
 	Heading float64 `nmea:"number"`
-	Status  string  `nmea:"string"`
+	Status  FAAMode `nmea:"string"`
 
 	Checksum byte `nmea:"checksum"`
 }
@@ -0,0 +1,130 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+)
+
+// Unmarshaler is the interface implemented by types that can parse
+// the fields of a NMEA 0183 sentence themselves. The fields start
+// with the sentence type and exclude the checksum. UnmarshalNMEA
+// must copy the fields if it wishes to retain them after returning.
+type Unmarshaler interface {
+	UnmarshalNMEA(fields []string) error
+}
+
+// Marshaler is the interface implemented by types that can format
+// themselves as a NMEA 0183 sentence. MarshalNMEA returns the fields
+// of the sentence, starting with the sentence type and excluding the
+// checksum.
+type Marshaler interface {
+	MarshalNMEA() ([]string, error)
+}
+
+// FieldUnmarshaler is the interface implemented by field types that can
+// parse a raw NMEA field value themselves. UnmarshalNMEAField is called
+// for empty fields as well as fields with values.
+type FieldUnmarshaler interface {
+	UnmarshalNMEAField(field string) error
+}
+
+// FieldMarshaler is the interface implemented by field types that can
+// format themselves as a NMEA field value.
+type FieldMarshaler interface {
+	MarshalNMEAField() (string, error)
+}
+
+var (
+	unmarshalerType      = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	fieldUnmarshalerType = reflect.TypeOf((*FieldUnmarshaler)(nil)).Elem()
+	textUnmarshalerType  = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isUnmarshaler returns whether values of type typ implement Unmarshaler.
+func isUnmarshaler(typ reflect.Type) bool {
+	return reflect.PtrTo(typ).Implements(unmarshalerType)
+}
+
+// unmarshal calls u's UnmarshalNMEA method with fields. If borrowed is
+// true the fields are copied before the call.
+func unmarshal(u Unmarshaler, fields []string, borrowed bool) error {
+	if borrowed {
+		clones := make([]string, len(fields))
+		for i, f := range fields {
+			clones[i] = cloneString(f)
+		}
+		fields = clones
+	}
+	return u.UnmarshalNMEA(fields)
+}
+
+// isText returns whether values of type typ can be filled by the "text"
+// method.
+func isText(typ reflect.Type) bool {
+	ptr := reflect.PtrTo(typ)
+	return ptr.Implements(fieldUnmarshalerType) || ptr.Implements(textUnmarshalerType)
+}
+
+// textMethod returns the method used to fill fields with the "text"
+// method. Fields are parsed by their UnmarshalNMEAField method or, if
+// they do not implement FieldUnmarshaler, their UnmarshalText method.
+// Empty NMEA values are not passed to UnmarshalText, and result in the
+// zero value. Fields are formatted by their MarshalNMEAField method or
+// their MarshalText method.
+func textMethod() method {
+	return method{
+		parse: func(dst reflect.Value, field string) error {
+			switch u := dst.Addr().Interface().(type) {
+			case FieldUnmarshaler:
+				return u.UnmarshalNMEAField(field)
+			case encoding.TextUnmarshaler:
+				if field == "" {
+					dst.Set(reflect.Zero(dst.Type()))
+					return nil
+				}
+				return u.UnmarshalText([]byte(field))
+			default:
+				return ErrType
+			}
+		},
+		format: func(dst []byte, src reflect.Value) ([]byte, error) {
+			var (
+				text string
+				err  error
+			)
+			switch m := addressable(src).Interface().(type) {
+			case FieldMarshaler:
+				text, err = m.MarshalNMEAField()
+			case encoding.TextMarshaler:
+				var b []byte
+				b, err = m.MarshalText()
+				text = string(b)
+			default:
+				return dst, ErrType
+			}
+			if err != nil {
+				return dst, err
+			}
+			if strings.ContainsAny(text, reserved) {
+				return dst, ErrReserved
+			}
+			return append(dst, text...), nil
+		},
+	}
+}
+
+// addressable returns the address of v, copying v if it is not
+// addressable.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v.Addr()
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p
+}
@@ -0,0 +1,139 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// mmsi is a maritime mobile service identity.
+type mmsi uint32
+
+func (m *mmsi) UnmarshalNMEAField(field string) error {
+	if field == "" {
+		*m = 0
+		return nil
+	}
+	if len(field) != 9 {
+		return fmt.Errorf("invalid MMSI length: %d", len(field))
+	}
+	v, err := strconv.ParseUint(field, 10, 32)
+	*m = mmsi(v)
+	return err
+}
+
+func (m mmsi) MarshalNMEAField() (string, error) {
+	if m == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("%09d", uint32(m)), nil
+}
+
+// waypointID is an upper case waypoint identifier.
+type waypointID string
+
+func (w *waypointID) UnmarshalText(text []byte) error {
+	*w = waypointID(strings.ToUpper(string(text)))
+	return nil
+}
+
+func (w waypointID) MarshalText() ([]byte, error) {
+	return []byte(w), nil
+}
+
+type textTest struct {
+	Type string `nmea:"PXTXT"`
+
+	MMSI     mmsi
+	Waypoint waypointID   `nmea:"text"`
+	Optional *waypointID  `nmea:"text"`
+	Route    []waypointID `nmea:"rest"`
+}
+
+func TestFieldUnmarshalers(t *testing.T) {
+	const sentence = "$PXTXT,235009802,pointa,,b,c*47"
+	var got textTest
+	err := ParseTo(&got, sentence)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := textTest{Type: "PXTXT", MMSI: 235009802, Waypoint: "POINTA", Route: []waypointID{"B", "C"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected result:\ngot: %#v\nwant:%#v", got, want)
+	}
+
+	s, err := Marshal(got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const wantSentence = "$PXTXT,235009802,POINTA,,B,C*47"
+	if s != wantSentence {
+		t.Errorf("unexpected marshal result: got:%q want:%q", s, wantSentence)
+	}
+
+	err = ParseToBytes(&got, []byte("$PXTXT,2350098,pointa"))
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *ParseError: got:%T", err)
+	}
+	if perr.Field != "MMSI" || perr.Method != "text" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err = Validate(struct {
+		Type string `nmea:"PXBAD"`
+		Text int    `nmea:"text"`
+	}{})
+	if !errors.Is(err, ErrType) {
+		t.Errorf("unexpected error for invalid text field: got:%v want:%v", err, ErrType)
+	}
+}
+
+// rawSentence is a sentence that parses its own fields.
+type rawSentence struct {
+	Type   string
+	Fields []string
+}
+
+func (s *rawSentence) UnmarshalNMEA(fields []string) error {
+	if len(fields) < 2 {
+		return ErrTooShort
+	}
+	s.Type = fields[0]
+	s.Fields = fields[1:]
+	return nil
+}
+
+func TestUnmarshaler(t *testing.T) {
+	r := NewRegistry()
+	r.Register("PXRAW", rawSentence{})
+
+	buf := []byte("$PXRAW,a,b,c*00")
+	got, err := r.ParseBytes(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The parsed fields must not refer to the input buffer.
+	copy(buf, "$PXRAW,x,y,z")
+	want := rawSentence{Type: "PXRAW", Fields: []string{"a", "b", "c"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected result:\ngot: %#v\nwant:%#v", got, want)
+	}
+
+	var dst rawSentence
+	err = ParseTo(&dst, "$PXRAW,a,b,c*01")
+	if !errors.Is(err, ErrChecksum) {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrChecksum)
+	}
+	err = ParseTo(&dst, "$PXRAW")
+	if err != ErrTooShort {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrTooShort)
+	}
+}
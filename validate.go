@@ -0,0 +1,122 @@
+// Copyright ©2019 Dan Kortschak. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nmea
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TagError describes a problem with the "nmea" tag of a struct field.
+type TagError struct {
+	Type  reflect.Type // Type is the struct type holding the field.
+	Field string       // Field is the name of the field.
+	Tag   string       // Tag is the field's "nmea" tag.
+	Err   error        // Err is the reason the tag is invalid.
+}
+
+func (e *TagError) Error() string {
+	reason := strings.TrimPrefix(e.Err.Error(), "nmea: ")
+	if e.Field == "" {
+		return fmt.Sprintf("nmea: invalid struct %s: %s", e.Type, reason)
+	}
+	return fmt.Sprintf("nmea: invalid tag %q on %s.%s: %s", e.Tag, e.Type, e.Field, reason)
+}
+
+func (e *TagError) Unwrap() error { return e.Err }
+
+// TagErrors is the list of problems found by Validate.
+type TagErrors []*TagError
+
+func (e TagErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is returns whether any of the errors held by e matches target.
+// It allows errors.Is to examine the individual errors in Go versions
+// that do not use the Unwrap method's list of errors.
+func (e TagErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error held by e that matches target, as described
+// for errors.As.
+func (e TagErrors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the individual errors held by e.
+func (e TagErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Validate checks that the "nmea" tags of the struct held by dst can be
+// used for parsing. The concrete value of dst must be a struct or a pointer
+// to a struct, unless it implements Unmarshaler, in which case Validate
+// returns nil. If any problems are found, they are all returned as a
+// TagErrors.
+//
+// Validate reports Type fields that are missing or are not the first field
+// of the struct, Type tags that are not valid regular expressions, unknown
+// methods and methods used on fields that they cannot fill. A method is
+// considered unable to fill a field if it returns ErrType or panics when
+// given the field's zero value.
+func Validate(dst interface{}) error {
+	rt := reflect.TypeOf(dst)
+	if rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt != nil && isUnmarshaler(rt) {
+		return nil
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	return planFor(rt).err
+}
+
+// canHold returns whether the method m can parse into and format from
+// values of type typ.
+func canHold(m method, typ reflect.Type) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+	v := reflect.New(typ).Elem()
+	if m.span > 1 {
+		if m.parseSpan(v, make([]string, m.span)) == ErrType {
+			return false
+		}
+	} else if m.parse(v, "") == ErrType {
+		return false
+	}
+	if m.format != nil {
+		if _, err := m.format(nil, v); err == ErrType {
+			return false
+		}
+	}
+	return true
+}